@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,20 +18,100 @@ type Config struct {
 	MinLinesForPageBreak int
 	CompactHeaderLines   int
 	FileSeparatorLines   int
-	SupportedExtensions  map[string]bool
+	// ✅ Cỡ chữ code (pt) và số ký tự mỗi hàng trước khi xuống hàng (0 = tự tính, xem layout.go)
+	CodeFontSize int
+	WrapWidth    int
+	// ✅ Lề trang (mm, 4 cạnh) và giãn dòng code (bội số cỡ chữ, 0 = tự tính để vừa đúng lines_per_page)
+	MarginMM    float64
+	LineSpacing float64
+	PageBreaks  string // PageBreaksFlow / PageBreaksFixed
+	Highlight   string // HighlightColor / HighlightMono / HighlightOff
+	// ✅ Tab, thụt lề, khoảng trắng cuối dòng (xem indentation.go)
+	Indentation         Indentation
+	SupportedExtensions map[string]bool
+	// ✅ Tên hiển thị, comment, marker sinh tự động của từng ngôn ngữ (xem languages.go)
+	Languages *LanguageRegistry
+	// ✅ Nhận diện file sinh tự động theo marker ở đầu file (xem generated.go)
+	GeneratedCode GeneratedCode
+	// ✅ Encoding của file source: nhận diện BOM / UTF-16 / UTF-8, fallback cho file cũ (xem encoding.go)
+	Encoding Encoding
+	// ✅ Dòng quá dài, file minified / một dòng: wrap, truncate hoặc exclude (xem longlines.go)
+	LongLines LongLines
+	// ✅ Xuống dòng, ký tự điều khiển, nhận diện file nhị phân (xem normalization.go)
+	Normalization Normalization
+	// ✅ Thứ tự file trong document: path, depth, priority, language, size, entry-points (xem ordering.go)
+	Ordering Ordering
 	// ✅ Thêm chức năng exclude files
 	ExcludeFiles    map[string]bool // Exclude exact filename
-	ExcludePatterns []string        // Exclude by pattern (contains)
+	ExcludePatterns []string        // Exclude by pattern (contains) - legacy
+	ExcludeRules    []string        // Exclude by path glob / regex, "!" để re-include
+	// ✅ Bật lại danh sách substring pattern mặc định cũ ("ip", "key", "env"...)
+	LegacyExcludePatterns bool
+	compiledRules         []ExcludeRule
+	// ✅ Đọc .gitignore / .copyrightignore (lồng nhau) khi scan
+	RespectGitignore   bool
+	UseCopyrightIgnore bool
+	// ✅ Thiết lập theo profile (xem profiles.go)
+	Profile               string
+	PageSize              string
+	ShortenThresholdPages int
+	ExcerptStrategy       string
+	ExcerptSections       int            // evenly-spaced: số đoạn (0 = tự tính, xem excerpt.go)
+	ExcerptRanges         []ExcerptRange // curated: file / dòng chọn tay
+	ExcerptBoundaries     string         // ExcerptBoundaryLines / Declarations / Files (xem profiles.go)
+	Cover                 CoverPage
+	// ✅ Mục lục sau trang bìa: TOCOff / TOCField / TOCStatic (xem layout.go)
+	TableOfContents string
+	// ✅ Che secret trong nội dung file trước khi render (xem redaction.go)
+	Redaction Redaction
+	// ✅ Đọc file từ git tại ref này (tag/branch/hash) thay vì working tree
+	GitRef string
+	// ✅ Output
+	OutputDir  string // Thư mục chứa file .docx
+	OutputName string // Tiền tố tên file: <OutputName>_<loại>_<timestamp>.docx
+	PageMap    string // Bản đồ trang ghi cạnh file .docx: PageMapOff / CSV / JSON / Both (xem layout.go)
+	Verbosity  int    // VerbosityQuiet / VerbosityNormal / VerbosityVerbose
+	// ✅ Nơi ghi log tiến trình (nil = stdout); scan --format=json dùng stderr để stdout chỉ có JSON
+	Output io.Writer
+	// ✅ File config đã được load (rỗng nếu chỉ dùng mặc định)
+	SourceFile string
 }
 
+// ✅ Mức độ log ra console
+const (
+	VerbosityQuiet = iota
+	VerbosityNormal
+	VerbosityVerbose
+)
+
 func LoadConfig() *Config {
-	return &Config{
+	cfg := &Config{
 		LinesPerPage:         70,
 		TargetPages:          75,
 		SectionPages:         26,
 		MinLinesForPageBreak: 45,
 		CompactHeaderLines:   2,
 		FileSeparatorLines:   1,
+		CodeFontSize:         9,
+		MarginMM:             defaultMarginMM,
+		PageBreaks:           PageBreaksFlow,
+		ExcerptBoundaries:    ExcerptBoundaryLines,
+		Highlight:            HighlightColor,
+		TableOfContents:      TOCOff,
+		Indentation:          defaultIndentation(),
+		OutputDir:            "copyright_documents",
+		OutputName:           "source_code",
+		PageMap:              PageMapCSV,
+		Verbosity:            VerbosityNormal,
+		RespectGitignore:     true,
+		UseCopyrightIgnore:   true,
+		Redaction:            defaultRedaction(),
+		Languages:            NewLanguageRegistry(),
+		GeneratedCode:        defaultGeneratedCode(),
+		Encoding:             defaultEncoding(),
+		LongLines:            defaultLongLines(),
+		Normalization:        defaultNormalization(),
+		Ordering:             defaultOrdering(),
 		SupportedExtensions: map[string]bool{
 			".cs":   true, // C#
 			".dart": true, // Dart
@@ -54,70 +135,282 @@ func LoadConfig() *Config {
 			"Enum.cs":                 true,
 			"Service.cs":              true,
 		},
-		// ✅ Patterns cần loại bỏ (contains match)
-		ExcludePatterns: []string{
-			"secret",     // Bất kỳ file nào chứa "secret"
-			"password",   // Bất kỳ file nào chứa "password"
-			"apikey",     // API keys
-			"config",     // General config files
-			"setting",    // Settings files
-			"credential", // Credentials
-			"token",      // Token files
-			"env",        // Environment files
-			"passcode",   // Passcode files
-			// ✅ Generated files patterns
-			".g.dart",       // Generated files từ build_runner (json_serializable, etc.)
-			".freezed.dart", // Generated files từ freezed package
-			".gr.dart",      // Generated files từ auto_route
-			".config.dart",  // Generated config files
-			".part.dart",    // Part files (thường là generated)
-			"Service.cs",
-			"AppController.cs",
-			"BIDV",
-			"Bank",
-			"VietinBank",
-			"Viettel",
-			"172.16.28",
-			"ip",
-			"key",
+		// ✅ Rule exclude theo đường dẫn (glob/regex, xem rules.go)
+		ExcludeRules: []string{
+			"name:*secret*",     // Bất kỳ file nào chứa "secret"
+			"name:*password*",   // Bất kỳ file nào chứa "password"
+			"name:*apikey*",     // API keys
+			"name:*api_key*",    // API keys
+			"name:*config*",     // General config files
+			"name:*setting*",    // Settings files
+			"name:*credential*", // Credentials
+			"name:*token*",      // Token files
+			"name:*passcode*",   // Passcode files
+			"name:.env",         // Environment files
+			"name:.env.*",
+			"name:*.env",
+			"name:*.key", // Key files
+			// ✅ Generated files
+			"name:*.g.dart",       // Generated files từ build_runner (json_serializable, etc.)
+			"name:*.freezed.dart", // Generated files từ freezed package
+			"name:*.gr.dart",      // Generated files từ auto_route
+			"name:*.config.dart",  // Generated config files
+			"name:*.part.dart",    // Part files (thường là generated)
+			"name:*Service.cs",
+			"name:*AppController.cs",
+			"name:*BIDV*",
+			"name:*Bank*",
+			"name:*Viettel*",
+			"name:*172.16.28*",
 		},
+		// ✅ Patterns kiểu cũ (contains match trên tên file); danh sách mặc định cũ
+		// chỉ được bật khi LegacyExcludePatterns = true
+		ExcludePatterns: []string{},
 	}
+
+	cfg.ApplyProfile(builtinProfiles[DefaultProfile])
+	return cfg
 }
 
-// ✅ Hàm kiểm tra file có bị exclude không
-func (c *Config) IsFileExcluded(filename string) bool {
+// ✅ Danh sách substring pattern mặc định cũ (chế độ legacy).
+// Dễ loại nhầm file vô hại: "ip" khớp RecipeList.dart, "key" khớp Keyboard.cs...
+var legacyExcludePatterns = []string{
+	"secret", "password", "apikey", "config", "setting", "credential", "token", "env", "passcode",
+	".g.dart", ".freezed.dart", ".gr.dart", ".config.dart", ".part.dart",
+	"Service.cs", "AppController.cs",
+	"BIDV", "Bank", "VietinBank", "Viettel", "172.16.28",
+	"ip", "key",
+}
+
+// ✅ Loại điều kiện đã quyết định việc exclude (dùng cho báo cáo giải thích)
+const (
+	MatchExactName       = "exact-name"
+	MatchPattern         = "pattern"
+	MatchGeneratedSuffix = "generated-suffix"
+	MatchRule            = "rule"
+)
+
+// ExclusionMatch mô tả điều kiện cuối cùng đã khớp với file.
+// Kind rỗng nghĩa là không có điều kiện nào khớp (file được giữ lại).
+type ExclusionMatch struct {
+	Excluded bool
+	Kind     string
+	Rule     string
+}
+
+// ✅ Hàm kiểm tra file có bị exclude không.
+// relPath là đường dẫn tương đối so với thư mục gốc (dùng "/").
+func (c *Config) IsFileExcluded(relPath string) bool {
+	return c.MatchExclusion(relPath).Excluded
+}
+
+// MatchExclusion trả về điều kiện đã quyết định việc exclude file.
+func (c *Config) MatchExclusion(relPath string) ExclusionMatch {
+	filename := relPath
+	if idx := strings.LastIndex(relPath, "/"); idx >= 0 {
+		filename = relPath[idx+1:]
+	}
 	// Chuẩn hóa filename về lowercase
 	lowerFilename := strings.ToLower(filename)
 
+	var match ExclusionMatch
+
 	// 1. Kiểm tra exact match
 	if c.ExcludeFiles[lowerFilename] {
-		return true
+		match = ExclusionMatch{Excluded: true, Kind: MatchExactName, Rule: lowerFilename}
 	}
 
 	// 2. Kiểm tra patterns (contains match)
-	for _, pattern := range c.ExcludePatterns {
-		if strings.Contains(lowerFilename, strings.ToLower(pattern)) {
-			return true
+	if !match.Excluded {
+		for _, pattern := range c.activeExcludePatterns() {
+			if strings.Contains(lowerFilename, strings.ToLower(pattern)) {
+				match = ExclusionMatch{Excluded: true, Kind: MatchPattern, Rule: pattern}
+				break
+			}
 		}
 	}
 
-	// 3. ✅ Thêm kiểm tra suffix cho generated files (để chắc chắn)
-	generatedSuffixes := []string{
-		".g.dart",
-		".freezed.dart",
-		".gr.dart",
-		".config.dart",
+	// 3. ✅ Suffix của file sinh tự động, theo ngôn ngữ (xem languages.go)
+	if !match.Excluded {
+		if suffix := c.generatedSuffixFor(lowerFilename); suffix != "" {
+			match = ExclusionMatch{Excluded: true, Kind: MatchGeneratedSuffix, Rule: "*" + suffix}
+		}
 	}
 
-	for _, suffix := range generatedSuffixes {
-		if strings.HasSuffix(lowerFilename, suffix) {
-			return true
+	// 4. ✅ Rule theo đường dẫn: rule khớp cuối cùng quyết định ("!" để re-include)
+	for _, rule := range c.excludeRules() {
+		if rule.Matches(relPath) {
+			match = ExclusionMatch{Excluded: !rule.Negate, Kind: MatchRule, Rule: rule.Source}
+		}
+	}
+
+	return match
+}
+
+// activeExcludePatterns trả về substring pattern đang có hiệu lực.
+func (c *Config) activeExcludePatterns() []string {
+	if !c.LegacyExcludePatterns {
+		return c.ExcludePatterns
+	}
+	return append(append([]string{}, legacyExcludePatterns...), c.ExcludePatterns...)
+}
+
+// excludeRules trả về rule đã compile (rule sai đã bị Validate chặn từ trước).
+func (c *Config) excludeRules() []ExcludeRule {
+	if c.compiledRules == nil {
+		rules, err := c.compileExcludeRules()
+		if err != nil {
+			fmt.Fprintf(c.Out(), "⚠️  %v\n", err)
+		}
+		c.compiledRules = rules
+	}
+	return c.compiledRules
+}
+
+// ✅ Kiểm tra giá trị config, lỗi ghi rõ tên key bị sai
+func (c *Config) Validate() error {
+	positive := []struct {
+		key   string
+		value int
+	}{
+		{"lines_per_page", c.LinesPerPage},
+		{"target_pages", c.TargetPages},
+		{"section_pages", c.SectionPages},
+	}
+	for _, field := range positive {
+		if field.value <= 0 {
+			return fmt.Errorf("invalid config key %q: must be greater than 0 (got %d)", field.key, field.value)
+		}
+	}
+
+	nonNegative := []struct {
+		key   string
+		value int
+	}{
+		{"min_lines_for_page_break", c.MinLinesForPageBreak},
+		{"compact_header_lines", c.CompactHeaderLines},
+		{"file_separator_lines", c.FileSeparatorLines},
+	}
+	for _, field := range nonNegative {
+		if field.value < 0 {
+			return fmt.Errorf("invalid config key %q: must not be negative (got %d)", field.key, field.value)
+		}
+	}
+
+	if c.ShortenThresholdPages < 0 {
+		return fmt.Errorf("invalid config key %q: must not be negative (got %d)",
+			"shorten_threshold_pages", c.ShortenThresholdPages)
+	}
+
+	if _, ok := PageSizes[c.PageSize]; !ok {
+		return fmt.Errorf("invalid config key %q: unknown page size %q (available: A4, Letter, Legal)",
+			"page_size", c.PageSize)
+	}
+
+	if err := c.validateExcerpt(); err != nil {
+		return err
+	}
+
+	if c.Cover.Language != "vi" && c.Cover.Language != "en" {
+		return fmt.Errorf("invalid config key %q: must be \"vi\" or \"en\" (got %q)",
+			"cover.language", c.Cover.Language)
+	}
+	if _, err := c.Cover.ParseCompletionDate(); err != nil {
+		return fmt.Errorf("invalid config key %q: must be YYYY-MM-DD (got %q)",
+			"cover.completion_date", c.Cover.CompletionDate)
+	}
+
+	if c.MinLinesForPageBreak > c.LinesPerPage {
+		return fmt.Errorf("invalid config key %q: must not exceed lines_per_page (%d > %d)",
+			"min_lines_for_page_break", c.MinLinesForPageBreak, c.LinesPerPage)
+	}
+
+	if len(c.SupportedExtensions) == 0 {
+		return fmt.Errorf("invalid config key %q: at least one extension is required", "supported_extensions")
+	}
+	for ext := range c.SupportedExtensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 {
+			return fmt.Errorf("invalid config key %q: extension %q must look like \".ext\"", "supported_extensions", ext)
 		}
 	}
 
+	if strings.TrimSpace(c.OutputDir) == "" {
+		return fmt.Errorf("invalid config key %q: must not be empty", "output_dir")
+	}
+	if strings.TrimSpace(c.OutputName) == "" || strings.ContainsAny(c.OutputName, `/\\`) {
+		return fmt.Errorf("invalid config key %q: must be a file name without directories (got %q)",
+			"output_name", c.OutputName)
+	}
+
+	for i, pattern := range c.ExcludePatterns {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("invalid config key %q: entry %d is empty", "exclude_patterns", i)
+		}
+	}
+
+	if err := c.validateLayout(); err != nil {
+		return err
+	}
+
+	if err := c.Indentation.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Languages.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Encoding.validate(); err != nil {
+		return err
+	}
+
+	if err := c.LongLines.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Normalization.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Ordering.validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateGeneratedCode(); err != nil {
+		return err
+	}
+
+	if err := c.Redaction.validate(); err != nil {
+		return err
+	}
+
+	rules, err := c.compileExcludeRules()
+	if err != nil {
+		return err
+	}
+	c.compiledRules = rules
+
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
 	return false
 }
 
+// ✅ Hàm thêm extension được hỗ trợ (chấp nhận cả "cs" lẫn ".cs")
+func (c *Config) AddSupportedExtension(ext string) {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	c.SupportedExtensions[ext] = true
+}
+
 // ✅ Hàm thêm file exclude runtime (nếu cần)
 func (c *Config) AddExcludeFile(filename string) {
 	c.ExcludeFiles[strings.ToLower(filename)] = true
@@ -128,23 +421,53 @@ func (c *Config) AddExcludePattern(pattern string) {
 	c.ExcludePatterns = append(c.ExcludePatterns, pattern)
 }
 
+// Out trả về nơi ghi log tiến trình.
+func (c *Config) Out() io.Writer {
+	if c.Output == nil {
+		return os.Stdout
+	}
+	return c.Output
+}
+
+// ✅ In log nếu mức verbosity hiện tại >= level
+func (c *Config) Logf(level int, format string, args ...interface{}) {
+	if c.Verbosity >= level {
+		fmt.Fprintf(c.Out(), format, args...)
+	}
+}
+
 // ✅ Hàm in danh sách exclude để debug
 func (c *Config) PrintExcludeList() {
-	fmt.Printf("🚫 Excluded files (exact match):\n")
+	fmt.Fprintf(c.Out(), "🚫 Excluded files (exact match):\n")
 	for file := range c.ExcludeFiles {
-		fmt.Printf("   - %s\n", file)
+		fmt.Fprintf(c.Out(), "   - %s\n", file)
+	}
+
+	if c.LegacyExcludePatterns {
+		fmt.Fprintf(c.Out(), "🚫 Excluded patterns (contains, legacy mode):\n")
+	} else {
+		fmt.Fprintf(c.Out(), "🚫 Excluded patterns (contains):\n")
+	}
+	for _, pattern := range c.activeExcludePatterns() {
+		fmt.Fprintf(c.Out(), "   - *%s*\n", pattern)
 	}
 
-	fmt.Printf("🚫 Excluded patterns (contains):\n")
-	for _, pattern := range c.ExcludePatterns {
-		fmt.Printf("   - *%s*\n", pattern)
+	fmt.Fprintf(c.Out(), "🚫 Exclude rules (path glob/regex, last match wins):\n")
+	for _, rule := range c.ExcludeRules {
+		fmt.Fprintf(c.Out(), "   - %s\n", rule)
 	}
 
-	fmt.Printf("🚫 Generated file suffixes will also be excluded:\n")
-	fmt.Printf("   - *.g.dart\n")
-	fmt.Printf("   - *.freezed.dart\n")
-	fmt.Printf("   - *.gr.dart\n")
-	fmt.Printf("   - *.config.dart\n")
+	fmt.Fprintf(c.Out(), "🚫 Generated file suffixes will also be excluded:\n")
+	for ext, enabled := range c.SupportedExtensions {
+		if !enabled {
+			continue
+		}
+		for _, suffix := range c.LanguageFor(ext).GeneratedSuffixes {
+			if strings.HasSuffix(suffix, ext) {
+				fmt.Fprintf(c.Out(), "   - *%s\n", suffix)
+			}
+		}
+	}
 }
 
 // Các hàm khác giữ nguyên...
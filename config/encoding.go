@@ -0,0 +1,109 @@
+// encoding.go - Source file text encoding settings (auto-detect, fallback, per-glob overrides)
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ✅ EncodingAuto: nhận diện theo BOM + heuristic
+const EncodingAuto = "auto"
+
+// Encoding là cấu hình encoding của file source.
+type Encoding struct {
+	Default   string // "auto" hoặc tên encoding (utf-8, utf-16le, windows-1258...)
+	Fallback  string // Dùng khi file không có BOM và không phải UTF-8 hợp lệ
+	Overrides []EncodingOverride
+	compiled  []compiledEncodingOverride
+}
+
+// EncodingOverride ép encoding cho các file khớp glob (cú pháp giống exclude_rules).
+type EncodingOverride struct {
+	Pattern  string `yaml:"pattern" json:"pattern"`
+	Encoding string `yaml:"encoding" json:"encoding"`
+}
+
+type compiledEncodingOverride struct {
+	regex    *regexp.Regexp
+	encoding string
+}
+
+// ✅ EncodingConfig là phần encoding khai báo trong file config.
+type EncodingConfig struct {
+	Default   string             `yaml:"default,omitempty" json:"default,omitempty"`
+	Fallback  string             `yaml:"fallback,omitempty" json:"fallback,omitempty"`
+	Overrides []EncodingOverride `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+func defaultEncoding() Encoding {
+	// Windows-1258: comment tiếng Việt trong project cũ trên Windows
+	return Encoding{Default: EncodingAuto, Fallback: "windows-1258"}
+}
+
+// ApplyTo ghi đè các field đã khai báo (overrides thay thế hoàn toàn).
+func (ec *EncodingConfig) ApplyTo(encoding *Encoding) {
+	if ec.Default != "" {
+		encoding.Default = ec.Default
+	}
+	if ec.Fallback != "" {
+		encoding.Fallback = ec.Fallback
+	}
+	if ec.Overrides != nil {
+		encoding.Overrides = append([]EncodingOverride{}, ec.Overrides...)
+		encoding.compiled = nil
+	}
+}
+
+func (e *Encoding) toFileConfig() *EncodingConfig {
+	return &EncodingConfig{
+		Default:   e.Default,
+		Fallback:  e.Fallback,
+		Overrides: append([]EncodingOverride{}, e.Overrides...),
+	}
+}
+
+func (e *Encoding) validate() error {
+	if e.Default != EncodingAuto {
+		if _, err := htmlindex.Get(e.Default); err != nil {
+			return fmt.Errorf("invalid config key %q: unknown encoding %q", "encoding.default", e.Default)
+		}
+	}
+	if _, err := htmlindex.Get(e.Fallback); err != nil {
+		return fmt.Errorf("invalid config key %q: unknown encoding %q", "encoding.fallback", e.Fallback)
+	}
+
+	compiled := make([]compiledEncodingOverride, 0, len(e.Overrides))
+	for _, override := range e.Overrides {
+		if _, err := htmlindex.Get(override.Encoding); err != nil {
+			return fmt.Errorf("invalid config key %q: unknown encoding %q for %q",
+				"encoding.overrides", override.Encoding, override.Pattern)
+		}
+		regex, err := CompileGlob(strings.TrimSpace(override.Pattern), true)
+		if err != nil || strings.TrimSpace(override.Pattern) == "" {
+			return fmt.Errorf("invalid config key %q: invalid pattern %q", "encoding.overrides", override.Pattern)
+		}
+		compiled = append(compiled, compiledEncodingOverride{regex: regex, encoding: override.Encoding})
+	}
+	e.compiled = compiled
+	return nil
+}
+
+// EncodingFor trả về encoding được ép cho file (override khớp cuối cùng, rồi tới Default).
+// Trả về EncodingAuto nếu cần tự nhận diện.
+func (c *Config) EncodingFor(relPath string) string {
+	if c.Encoding.compiled == nil && len(c.Encoding.Overrides) > 0 {
+		if err := c.Encoding.validate(); err != nil {
+			fmt.Fprintf(c.Out(), "⚠️  %v\n", err)
+		}
+	}
+
+	for i := len(c.Encoding.compiled) - 1; i >= 0; i-- {
+		if c.Encoding.compiled[i].regex.MatchString(relPath) {
+			return c.Encoding.compiled[i].encoding
+		}
+	}
+	return c.Encoding.Default
+}
@@ -0,0 +1,92 @@
+// excerpt.go - Shortened document settings: strategy, number of sections and curated file / line ranges
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExcerptRange là một file (glob, đường dẫn tương đối) và khoảng dòng được chọn tay cho
+// excerpt_strategy: curated. Lines rỗng = cả file.
+type ExcerptRange struct {
+	File  string `yaml:"file" json:"file"`
+	Lines string `yaml:"lines,omitempty" json:"lines,omitempty"` // "120-340" hoặc "120" (từ 1)
+}
+
+// LineSpan trả về dòng đầu / cuối (từ 1); 0, 0 nghĩa là cả file.
+func (r ExcerptRange) LineSpan() (first, last int, err error) {
+	if strings.TrimSpace(r.Lines) == "" {
+		return 0, 0, nil
+	}
+	firstText, lastText, isSpan := strings.Cut(r.Lines, "-")
+	if first, err = strconv.Atoi(strings.TrimSpace(firstText)); err != nil {
+		return 0, 0, fmt.Errorf("invalid line range %q (expected N or N-M)", r.Lines)
+	}
+	last = first
+	if isSpan {
+		if last, err = strconv.Atoi(strings.TrimSpace(lastText)); err != nil {
+			return 0, 0, fmt.Errorf("invalid line range %q (expected N or N-M)", r.Lines)
+		}
+	}
+	if first < 1 || last < first {
+		return 0, 0, fmt.Errorf("invalid line range %q (lines start at 1 and N must not exceed M)", r.Lines)
+	}
+	return first, last, nil
+}
+
+// ParseExcerptRange đọc giá trị của --excerpt-range: "glob" hoặc "glob:N-M" (kiểm tra ở Validate).
+func ParseExcerptRange(value string) ExcerptRange {
+	if i := strings.LastIndex(value, ":"); i >= 0 {
+		return ExcerptRange{File: value[:i], Lines: value[i+1:]}
+	}
+	return ExcerptRange{File: value}
+}
+
+// ExcerptSectionCount trả về số đoạn của excerpt_strategy: evenly-spaced
+// (excerpt_sections, 0 = target_pages / section_pages làm tròn lên).
+func (c *Config) ExcerptSectionCount() int {
+	if c.ExcerptSections > 0 {
+		return c.ExcerptSections
+	}
+	return max(1, (c.TargetPages+c.SectionPages-1)/c.SectionPages)
+}
+
+func (c *Config) validateExcerpt() error {
+	if !containsString(ExcerptStrategies, c.ExcerptStrategy) {
+		return fmt.Errorf("invalid config key %q: unknown strategy %q (available: %s)",
+			"excerpt_strategy", c.ExcerptStrategy, strings.Join(ExcerptStrategies, ", "))
+	}
+	if c.ExcerptSections < 0 || c.ExcerptSections > c.TargetPages {
+		return fmt.Errorf("invalid config key %q: must be between 0 (auto) and target_pages %d (got %d)",
+			"excerpt_sections", c.TargetPages, c.ExcerptSections)
+	}
+	for i, r := range c.ExcerptRanges {
+		key := fmt.Sprintf("excerpt_ranges[%d]", i)
+		if _, err := CompileGlob(r.File, true); err != nil {
+			return fmt.Errorf("invalid config key %q: file %q: %v", key, r.File, err)
+		}
+		if _, _, err := r.LineSpan(); err != nil {
+			return fmt.Errorf("invalid config key %q: %v", key, err)
+		}
+	}
+	if c.ExcerptStrategy == ExcerptCurated && len(c.ExcerptRanges) == 0 {
+		return fmt.Errorf("invalid config key %q: strategy %q needs at least one entry in excerpt_ranges",
+			"excerpt_strategy", ExcerptCurated)
+	}
+
+	if !containsString(ExcerptBoundaries, c.ExcerptBoundaries) {
+		return fmt.Errorf("invalid config key %q: unknown mode %q (available: %s)",
+			"excerpt_boundaries", c.ExcerptBoundaries, strings.Join(ExcerptBoundaries, ", "))
+	}
+	// page_breaks: fixed trích nguyên trang của file đầy đủ
+	if c.PageBreaks == PageBreaksFixed && c.ExcerptBoundaries != ExcerptBoundaryLines {
+		return fmt.Errorf("invalid config key %q: %q requires page_breaks: %s (fixed mode excerpts whole pages)",
+			"excerpt_boundaries", c.ExcerptBoundaries, PageBreaksFlow)
+	}
+	if c.PageBreaks == PageBreaksFixed && c.ExcerptStrategy == ExcerptCurated {
+		return fmt.Errorf("invalid config key %q: %q requires page_breaks: %s (fixed mode excerpts whole pages)",
+			"excerpt_strategy", ExcerptCurated, PageBreaksFlow)
+	}
+	return nil
+}
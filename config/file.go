@@ -0,0 +1,381 @@
+// file.go - Load per-project settings from copyright.yaml / copyright.json
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ✅ Tên file config được tự động tìm trong thư mục scan (theo thứ tự ưu tiên)
+var ConfigFileNames = []string{
+	"copyright.yaml",
+	"copyright.yml",
+	"copyright.json",
+}
+
+// FileConfig là dạng của copyright.yaml / copyright.json.
+// Field nil (hoặc list không khai báo) nghĩa là giữ nguyên giá trị mặc định.
+type FileConfig struct {
+	Profile              string                   `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Profiles             map[string]ProfileConfig `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	PageSize             *string                  `yaml:"page_size,omitempty" json:"page_size,omitempty"`
+	ShortenThreshold     *int                     `yaml:"shorten_threshold_pages,omitempty" json:"shorten_threshold_pages,omitempty"`
+	ExcerptStrategy      *string                  `yaml:"excerpt_strategy,omitempty" json:"excerpt_strategy,omitempty"`
+	ExcerptSections      *int                     `yaml:"excerpt_sections,omitempty" json:"excerpt_sections,omitempty"`
+	ExcerptRanges        []ExcerptRange           `yaml:"excerpt_ranges,omitempty" json:"excerpt_ranges,omitempty"`
+	ExcerptBoundaries    *string                  `yaml:"excerpt_boundaries,omitempty" json:"excerpt_boundaries,omitempty"`
+	Cover                *CoverConfig             `yaml:"cover,omitempty" json:"cover,omitempty"`
+	TableOfContents      *string                  `yaml:"table_of_contents,omitempty" json:"table_of_contents,omitempty"`
+	LinesPerPage         *int                     `yaml:"lines_per_page,omitempty" json:"lines_per_page,omitempty"`
+	TargetPages          *int                     `yaml:"target_pages,omitempty" json:"target_pages,omitempty"`
+	SectionPages         *int                     `yaml:"section_pages,omitempty" json:"section_pages,omitempty"`
+	MinLinesForPageBreak *int                     `yaml:"min_lines_for_page_break,omitempty" json:"min_lines_for_page_break,omitempty"`
+	CompactHeaderLines   *int                     `yaml:"compact_header_lines,omitempty" json:"compact_header_lines,omitempty"`
+	FileSeparatorLines   *int                     `yaml:"file_separator_lines,omitempty" json:"file_separator_lines,omitempty"`
+	CodeFontSize         *int                     `yaml:"code_font_size,omitempty" json:"code_font_size,omitempty"`
+	WrapWidth            *int                     `yaml:"wrap_width,omitempty" json:"wrap_width,omitempty"`
+	MarginMM             *float64                 `yaml:"margin_mm,omitempty" json:"margin_mm,omitempty"`
+	LineSpacing          *float64                 `yaml:"line_spacing,omitempty" json:"line_spacing,omitempty"`
+	PageBreaks           *string                  `yaml:"page_breaks,omitempty" json:"page_breaks,omitempty"`
+	PageMap              *string                  `yaml:"page_map,omitempty" json:"page_map,omitempty"`
+	Highlight            *string                  `yaml:"highlight,omitempty" json:"highlight,omitempty"`
+	Indentation          *IndentationConfig       `yaml:"indentation,omitempty" json:"indentation,omitempty"`
+	Languages            []Language               `yaml:"languages,omitempty" json:"languages,omitempty"`
+	GeneratedCode        *GeneratedCodeConfig     `yaml:"generated_code,omitempty" json:"generated_code,omitempty"`
+	Encoding             *EncodingConfig          `yaml:"encoding,omitempty" json:"encoding,omitempty"`
+	LongLines            *LongLinesConfig         `yaml:"long_lines,omitempty" json:"long_lines,omitempty"`
+	Normalization        *NormalizationConfig     `yaml:"normalization,omitempty" json:"normalization,omitempty"`
+	Ordering             *OrderingConfig          `yaml:"ordering,omitempty" json:"ordering,omitempty"`
+	SupportedExtensions  []string                 `yaml:"supported_extensions,omitempty" json:"supported_extensions,omitempty"`
+	ExcludeFiles         []string                 `yaml:"exclude_files,omitempty" json:"exclude_files,omitempty"`
+	ExcludePatterns      []string                 `yaml:"exclude_patterns,omitempty" json:"exclude_patterns,omitempty"`
+	ExcludeRules         []string                 `yaml:"exclude_rules,omitempty" json:"exclude_rules,omitempty"`
+	LegacyPatterns       *bool                    `yaml:"legacy_exclude_patterns,omitempty" json:"legacy_exclude_patterns,omitempty"`
+	RespectGitignore     *bool                    `yaml:"respect_gitignore,omitempty" json:"respect_gitignore,omitempty"`
+	CopyrightIgnore      *bool                    `yaml:"use_copyrightignore,omitempty" json:"use_copyrightignore,omitempty"`
+	Redaction            *RedactionConfig         `yaml:"redaction,omitempty" json:"redaction,omitempty"`
+	GitRef               *string                  `yaml:"git_ref,omitempty" json:"git_ref,omitempty"`
+	OutputDir            *string                  `yaml:"output_dir,omitempty" json:"output_dir,omitempty"`
+	OutputName           *string                  `yaml:"output_name,omitempty" json:"output_name,omitempty"`
+}
+
+// LoadForProject trả về config mặc định đã được merge với file config của project.
+// explicitPath (từ flag) được ưu tiên; nếu rỗng thì tìm ConfigFileNames trong rootDir.
+// Thứ tự áp dụng: mặc định → profile → key trong file config.
+// profileName (từ flag) được ưu tiên hơn `profile:` trong file.
+func LoadForProject(rootDir, explicitPath, profileName string) (*Config, error) {
+	cfg := LoadConfig()
+
+	path := explicitPath
+	if path == "" {
+		path = FindConfigFile(rootDir)
+	}
+
+	fileCfg := &FileConfig{}
+	if path != "" {
+		var err error
+		if fileCfg, err = ReadConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if profileName == "" {
+		profileName = fileCfg.Profile
+	}
+	if profileName != "" {
+		profile, err := ResolveProfile(profileName, fileCfg.Profiles)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ApplyProfile(profile)
+	}
+
+	fileCfg.ApplyTo(cfg)
+	if err := cfg.Validate(); err != nil {
+		if path == "" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	cfg.SourceFile = path
+	return cfg, nil
+}
+
+// FindConfigFile tìm file config trong rootDir, trả về "" nếu không có.
+func FindConfigFile(rootDir string) string {
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(rootDir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// ReadConfigFile đọc file YAML hoặc JSON (theo extension).
+// Key không tồn tại sẽ báo lỗi kèm tên key thay vì bị bỏ qua.
+func ReadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	fileCfg := &FileConfig{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(fileCfg); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(fileCfg); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format (use .yaml, .yml or .json)", path)
+	}
+
+	return fileCfg, nil
+}
+
+// ApplyTo ghi đè các giá trị đã khai báo lên cfg.
+// List (extensions, exclude) thay thế hoàn toàn list mặc định.
+func (fc *FileConfig) ApplyTo(cfg *Config) {
+	if fc.LinesPerPage != nil {
+		cfg.LinesPerPage = *fc.LinesPerPage
+	}
+	if fc.TargetPages != nil {
+		cfg.TargetPages = *fc.TargetPages
+	}
+	if fc.SectionPages != nil {
+		cfg.SectionPages = *fc.SectionPages
+	}
+	if fc.MinLinesForPageBreak != nil {
+		cfg.MinLinesForPageBreak = *fc.MinLinesForPageBreak
+	}
+	if fc.CompactHeaderLines != nil {
+		cfg.CompactHeaderLines = *fc.CompactHeaderLines
+	}
+	if fc.FileSeparatorLines != nil {
+		cfg.FileSeparatorLines = *fc.FileSeparatorLines
+	}
+	if fc.CodeFontSize != nil {
+		cfg.CodeFontSize = *fc.CodeFontSize
+	}
+	if fc.WrapWidth != nil {
+		cfg.WrapWidth = *fc.WrapWidth
+	}
+	if fc.MarginMM != nil {
+		cfg.MarginMM = *fc.MarginMM
+	}
+	if fc.LineSpacing != nil {
+		cfg.LineSpacing = *fc.LineSpacing
+	}
+	if fc.PageBreaks != nil {
+		cfg.PageBreaks = *fc.PageBreaks
+	}
+	if fc.PageMap != nil {
+		cfg.PageMap = *fc.PageMap
+	}
+	if fc.Highlight != nil {
+		cfg.Highlight = *fc.Highlight
+	}
+	if fc.TableOfContents != nil {
+		cfg.TableOfContents = *fc.TableOfContents
+	}
+	if fc.Indentation != nil {
+		fc.Indentation.ApplyTo(&cfg.Indentation)
+	}
+
+	if fc.PageSize != nil {
+		cfg.PageSize = *fc.PageSize
+	}
+	if fc.ShortenThreshold != nil {
+		cfg.ShortenThresholdPages = *fc.ShortenThreshold
+	}
+	if fc.ExcerptStrategy != nil {
+		cfg.ExcerptStrategy = *fc.ExcerptStrategy
+	}
+	if fc.ExcerptSections != nil {
+		cfg.ExcerptSections = *fc.ExcerptSections
+	}
+	if fc.ExcerptRanges != nil {
+		cfg.ExcerptRanges = append([]ExcerptRange{}, fc.ExcerptRanges...)
+	}
+	if fc.ExcerptBoundaries != nil {
+		cfg.ExcerptBoundaries = *fc.ExcerptBoundaries
+	}
+	if fc.Cover != nil {
+		fc.Cover.ApplyTo(&cfg.Cover)
+	}
+
+	if fc.Redaction != nil {
+		fc.Redaction.ApplyTo(&cfg.Redaction)
+	}
+
+	if fc.GitRef != nil {
+		cfg.GitRef = *fc.GitRef
+	}
+	if fc.OutputDir != nil {
+		cfg.OutputDir = *fc.OutputDir
+	}
+	if fc.OutputName != nil {
+		cfg.OutputName = *fc.OutputName
+	}
+
+	if fc.SupportedExtensions != nil {
+		cfg.SupportedExtensions = make(map[string]bool)
+		for _, ext := range fc.SupportedExtensions {
+			cfg.AddSupportedExtension(ext)
+		}
+	}
+	// ✅ Ngôn ngữ khai báo thêm được bật luôn (kể cả khi supported_extensions không liệt kê)
+	for _, lang := range fc.Languages {
+		cfg.Languages.Register(lang)
+		for _, ext := range lang.Extensions {
+			cfg.AddSupportedExtension(ext)
+		}
+	}
+	if fc.Encoding != nil {
+		fc.Encoding.ApplyTo(&cfg.Encoding)
+	}
+	if fc.LongLines != nil {
+		fc.LongLines.ApplyTo(&cfg.LongLines)
+	}
+	if fc.Normalization != nil {
+		fc.Normalization.ApplyTo(&cfg.Normalization)
+	}
+	if fc.Ordering != nil {
+		fc.Ordering.ApplyTo(&cfg.Ordering)
+	}
+	if fc.GeneratedCode != nil {
+		fc.GeneratedCode.ApplyTo(&cfg.GeneratedCode)
+	}
+	if fc.ExcludeFiles != nil {
+		cfg.ExcludeFiles = make(map[string]bool)
+		for _, filename := range fc.ExcludeFiles {
+			cfg.AddExcludeFile(filename)
+		}
+	}
+	if fc.ExcludePatterns != nil {
+		cfg.ExcludePatterns = append([]string{}, fc.ExcludePatterns...)
+	}
+	if fc.ExcludeRules != nil {
+		cfg.ExcludeRules = append([]string{}, fc.ExcludeRules...)
+		cfg.compiledRules = nil
+	}
+	if fc.LegacyPatterns != nil {
+		cfg.LegacyExcludePatterns = *fc.LegacyPatterns
+	}
+	if fc.RespectGitignore != nil {
+		cfg.RespectGitignore = *fc.RespectGitignore
+	}
+	if fc.CopyrightIgnore != nil {
+		cfg.UseCopyrightIgnore = *fc.CopyrightIgnore
+	}
+}
+
+// ToFileConfig xuất config hiệu lực dưới dạng FileConfig (dùng cho `config print`).
+func (c *Config) ToFileConfig() *FileConfig {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	strPtr := func(v string) *string { return &v }
+	optionalStr := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+
+	extensions := make([]string, 0, len(c.SupportedExtensions))
+	for ext, enabled := range c.SupportedExtensions {
+		if enabled {
+			extensions = append(extensions, ext)
+		}
+	}
+	sort.Strings(extensions)
+
+	excludeFiles := make([]string, 0, len(c.ExcludeFiles))
+	for filename, enabled := range c.ExcludeFiles {
+		if enabled {
+			excludeFiles = append(excludeFiles, filename)
+		}
+	}
+	sort.Strings(excludeFiles)
+
+	enabled := c.Cover.Enabled
+	skipPageNumber := c.Cover.SkipPageNumber
+	legacy := c.LegacyExcludePatterns
+	gitignore := c.RespectGitignore
+	copyrightIgnore := c.UseCopyrightIgnore
+	return &FileConfig{
+		Profile:              c.Profile,
+		PageSize:             strPtr(c.PageSize),
+		ShortenThreshold:     intPtr(c.ShortenThresholdPages),
+		ExcerptStrategy:      strPtr(c.ExcerptStrategy),
+		ExcerptSections:      intPtr(c.ExcerptSections),
+		ExcerptRanges:        c.ExcerptRanges,
+		ExcerptBoundaries:    strPtr(c.ExcerptBoundaries),
+		LinesPerPage:         intPtr(c.LinesPerPage),
+		TargetPages:          intPtr(c.TargetPages),
+		SectionPages:         intPtr(c.SectionPages),
+		MinLinesForPageBreak: intPtr(c.MinLinesForPageBreak),
+		CompactHeaderLines:   intPtr(c.CompactHeaderLines),
+		FileSeparatorLines:   intPtr(c.FileSeparatorLines),
+		CodeFontSize:         intPtr(c.CodeFontSize),
+		WrapWidth:            intPtr(c.WrapWidth),
+		MarginMM:             floatPtr(c.MarginMM),
+		LineSpacing:          floatPtr(c.LineSpacing),
+		PageBreaks:           strPtr(c.PageBreaks),
+		PageMap:              strPtr(c.PageMap),
+		Highlight:            strPtr(c.Highlight),
+		TableOfContents:      strPtr(c.TableOfContents),
+		Indentation:          c.Indentation.toFileConfig(),
+		Languages:            c.Languages.Custom(),
+		GeneratedCode:        c.GeneratedCode.toFileConfig(),
+		Encoding:             c.Encoding.toFileConfig(),
+		LongLines:            c.LongLines.toFileConfig(),
+		Normalization:        c.Normalization.toFileConfig(),
+		Ordering:             c.Ordering.toFileConfig(),
+		SupportedExtensions:  extensions,
+		ExcludeFiles:         excludeFiles,
+		ExcludePatterns:      append([]string{}, c.ExcludePatterns...),
+		ExcludeRules:         append([]string{}, c.ExcludeRules...),
+		LegacyPatterns:       &legacy,
+		RespectGitignore:     &gitignore,
+		CopyrightIgnore:      &copyrightIgnore,
+		Redaction:            c.Redaction.toFileConfig(),
+		GitRef:               optionalStr(c.GitRef),
+		OutputDir:            strPtr(c.OutputDir),
+		OutputName:           strPtr(c.OutputName),
+		Cover: &CoverConfig{
+			Enabled:        &enabled,
+			Language:       c.Cover.Language,
+			Title:          c.Cover.Title,
+			SoftwareName:   c.Cover.SoftwareName,
+			Version:        c.Cover.Version,
+			Owner:          c.Cover.Owner,
+			Authors:        c.Cover.Authors,
+			CompletionDate: c.Cover.CompletionDate,
+			SkipPageNumber: &skipPageNumber,
+		},
+	}
+}
+
+// ToYAML trả về config hiệu lực dưới dạng YAML có thể dùng lại làm copyright.yaml.
+func (c *Config) ToYAML() ([]byte, error) {
+	return yaml.Marshal(c.ToFileConfig())
+}
@@ -0,0 +1,231 @@
+// generated.go - Detect generated code by markers in the first lines of a file
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ✅ Xử lý file có marker sinh tự động
+const (
+	GeneratedExclude = "exclude" // Loại khỏi tài liệu
+	GeneratedTag     = "tag"     // Giữ lại nhưng đánh dấu "generated"
+	GeneratedOff     = "off"     // Không kiểm tra marker
+)
+
+var generatedActions = []string{GeneratedExclude, GeneratedTag, GeneratedOff}
+
+// GeneratedCode là cấu hình nhận diện file sinh tự động theo nội dung đầu file.
+type GeneratedCode struct {
+	Action      string
+	HeaderLines int                 // Số dòng đầu file được kiểm tra
+	Markers     map[string][]string // Tên ngôn ngữ → marker, thay thế marker có sẵn trong registry
+}
+
+// ✅ GeneratedCodeConfig là phần generated_code khai báo trong file config.
+type GeneratedCodeConfig struct {
+	Action      string              `yaml:"action,omitempty" json:"action,omitempty"`
+	HeaderLines *int                `yaml:"header_lines,omitempty" json:"header_lines,omitempty"`
+	Markers     map[string][]string `yaml:"markers,omitempty" json:"markers,omitempty"`
+}
+
+func defaultGeneratedCode() GeneratedCode {
+	return GeneratedCode{Action: GeneratedExclude, HeaderLines: 20}
+}
+
+// ApplyTo ghi đè các field đã khai báo (marker được merge theo tên ngôn ngữ).
+func (gc *GeneratedCodeConfig) ApplyTo(generated *GeneratedCode) {
+	if gc.Action != "" {
+		generated.Action = gc.Action
+	}
+	if gc.HeaderLines != nil {
+		generated.HeaderLines = *gc.HeaderLines
+	}
+	if gc.Markers != nil {
+		if generated.Markers == nil {
+			generated.Markers = make(map[string][]string)
+		}
+		for name, markers := range gc.Markers {
+			generated.Markers[name] = append([]string{}, markers...)
+		}
+	}
+}
+
+func (g *GeneratedCode) toFileConfig() *GeneratedCodeConfig {
+	headerLines := g.HeaderLines
+	return &GeneratedCodeConfig{Action: g.Action, HeaderLines: &headerLines, Markers: g.Markers}
+}
+
+func (c *Config) validateGeneratedCode() error {
+	if !containsString(generatedActions, c.GeneratedCode.Action) {
+		return fmt.Errorf("invalid config key %q: unknown action %q (available: %s)",
+			"generated_code.action", c.GeneratedCode.Action, strings.Join(generatedActions, ", "))
+	}
+	if c.GeneratedCode.HeaderLines <= 0 {
+		return fmt.Errorf("invalid config key %q: must be greater than 0 (got %d)",
+			"generated_code.header_lines", c.GeneratedCode.HeaderLines)
+	}
+
+	names := make([]string, 0, len(c.GeneratedCode.Markers))
+	for name := range c.GeneratedCode.Markers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := c.Languages.ByName(name); !ok {
+			return fmt.Errorf("invalid config key %q: unknown language %q", "generated_code.markers", name)
+		}
+		if err := checkGeneratedMarkers(c.GeneratedCode.Markers[name]); err != nil {
+			return fmt.Errorf("invalid config key %q: %s: %v", "generated_code.markers", name, err)
+		}
+	}
+	return nil
+}
+
+// GeneratedMarkers trả về marker của ngôn ngữ ứng với extension (đã áp dụng override trong config).
+func (c *Config) GeneratedMarkers(ext string) []string {
+	lang := c.LanguageFor(ext)
+	for name, markers := range c.GeneratedCode.Markers {
+		if strings.EqualFold(name, lang.Name) {
+			return markers
+		}
+	}
+	return lang.GeneratedMarkers
+}
+
+// MatchGeneratedMarker tìm marker trong HeaderLines dòng đầu (phân biệt hoa thường).
+// Marker thường chỉ khớp trong phần comment của dòng (theo LineComment / BlockComment của ngôn ngữ);
+// marker "re:" là regex khớp với cả dòng (dòng `// Code generated ... DO NOT EDIT.` của Go,
+// `using ...Migrations;` của EF Core). line bắt đầu từ 1; ok = false nếu không có marker nào hoặc Action = off.
+func (c *Config) MatchGeneratedMarker(ext string, lines []string) (marker string, line int, ok bool) {
+	if c.GeneratedCode.Action == GeneratedOff {
+		return "", 0, false
+	}
+	markers := c.GeneratedMarkers(ext)
+	scanner := newCommentScanner(c.LanguageFor(ext))
+	for i := 0; i < len(lines) && i < c.GeneratedCode.HeaderLines; i++ {
+		comment := scanner.comment(lines[i])
+		for _, marker := range markers {
+			if expr, isRegex := strings.CutPrefix(marker, generatedRegexPrefix); isRegex {
+				if regex, err := compileGeneratedMarker(expr); err == nil && regex.MatchString(lines[i]) {
+					return marker, i + 1, true
+				}
+				continue
+			}
+			if marker != "" && strings.Contains(comment, marker) {
+				return marker, i + 1, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// generatedRegexPrefix đánh dấu marker là regex trên cả dòng thay vì chuỗi trong comment.
+const generatedRegexPrefix = "re:"
+
+var generatedMarkerRegexes sync.Map // expr → *regexp.Regexp
+
+// checkGeneratedMarkers kiểm tra các marker "re:" compile được.
+func checkGeneratedMarkers(markers []string) error {
+	for _, marker := range markers {
+		if expr, isRegex := strings.CutPrefix(marker, generatedRegexPrefix); isRegex {
+			if _, err := compileGeneratedMarker(expr); err != nil {
+				return fmt.Errorf("marker %q: %v", marker, err)
+			}
+		}
+	}
+	return nil
+}
+
+func compileGeneratedMarker(expr string) (*regexp.Regexp, error) {
+	if regex, ok := generatedMarkerRegexes.Load(expr); ok {
+		return regex.(*regexp.Regexp), nil
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	generatedMarkerRegexes.Store(expr, regex)
+	return regex, nil
+}
+
+// ✅ commentScanner tách phần comment của từng dòng, nhớ trạng thái block comment qua các dòng.
+// Chuỗi (theo Strings của ngôn ngữ) được bỏ qua để "//" trong "http://..." không bị coi là comment.
+type commentScanner struct {
+	lineComment string
+	blockStart  string
+	blockEnd    string
+	strings     []string
+	inBlock     bool
+}
+
+func newCommentScanner(lang Language) *commentScanner {
+	s := &commentScanner{lineComment: lang.LineComment, strings: lang.Strings}
+	if len(lang.BlockComment) == 2 {
+		s.blockStart, s.blockEnd = lang.BlockComment[0], lang.BlockComment[1]
+	}
+	if len(s.strings) == 0 {
+		s.strings = []string{`"`, "'"}
+	}
+	return s
+}
+
+// comment trả về nội dung comment trên dòng (các đoạn nối bằng khoảng trắng), "" nếu dòng không có comment.
+func (s *commentScanner) comment(line string) string {
+	var parts []string
+	for i := 0; i < len(line); {
+		if s.inBlock {
+			end := strings.Index(line[i:], s.blockEnd)
+			if end < 0 {
+				parts = append(parts, line[i:])
+				break
+			}
+			parts = append(parts, line[i:i+end])
+			i += end + len(s.blockEnd)
+			s.inBlock = false
+			continue
+		}
+		rest := line[i:]
+		switch {
+		case s.blockStart != "" && strings.HasPrefix(rest, s.blockStart):
+			i += len(s.blockStart)
+			s.inBlock = true
+			continue
+		case s.lineComment != "" && strings.HasPrefix(rest, s.lineComment):
+			parts = append(parts, rest[len(s.lineComment):])
+			return strings.Join(parts, " ")
+		}
+		if quote := s.stringDelimiter(rest); quote != "" {
+			i += len(quote) + stringLength(rest[len(quote):], quote)
+			continue
+		}
+		i++
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *commentScanner) stringDelimiter(rest string) string {
+	for _, quote := range s.strings {
+		if strings.HasPrefix(rest, quote) {
+			return quote
+		}
+	}
+	return ""
+}
+
+// stringLength trả về độ dài phần còn lại của chuỗi tính cả dấu đóng (hết dòng nếu chuỗi chưa đóng).
+func stringLength(rest, quote string) int {
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(rest[i:], quote) {
+			return i + len(quote)
+		}
+	}
+	return len(rest)
+}
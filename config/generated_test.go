@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchGeneratedMarker(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     string
+		content string
+		want    bool
+	}{
+		{"go convention", ".go", "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb", true},
+		{"go prose comment", ".go", "package main\n\n// Greeting is shown on start. Do not edit without legal review.\nconst Greeting = \"hi\"", false},
+		{"go both phrases apart", ".go", "// Code generated once by hand, then reviewed.\n// DO NOT EDIT the exported names.\npackage x", false},
+		{"python prose comment", ".py", "# Do not edit the constants below without updating the docs.\nRATE = 3", false},
+		{"python protobuf", ".py", "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\nimport sys", true},
+		{"case sensitive", ".cs", "// <AUTO-GENERATED>\nclass A {}", false},
+		{"csharp header", ".cs", "//------\n// <auto-generated>\n//     This code was generated by a tool.\n// </auto-generated>", true},
+		{"block comment", ".cs", "/*\n * <auto-generated />\n */\nclass A {}", true},
+		{"marker in string", ".cs", "class A {\n    string s = \"// <auto-generated>\";\n}", false},
+		{"url before comment", ".ts", "const url = \"http://x\"; // @generated", true},
+		{"ef migration", ".cs", "using System;\nusing Microsoft.EntityFrameworkCore.Migrations;\n\nnamespace Db.Migrations", true},
+		{"java annotation", ".java", "package a;\n\n@Generated(\"dagger\")\npublic final class A {}", true},
+		{"java javadoc mention", ".java", "/** Not @Generated, written by hand. */\nclass A {}", false},
+	}
+
+	cfg := LoadConfig()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marker, line, got := cfg.MatchGeneratedMarker(tt.ext, strings.Split(tt.content, "\n"))
+			if got != tt.want {
+				t.Errorf("MatchGeneratedMarker = %q at line %d (%v), want %v", marker, line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedMarkerRegexValidation(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.GeneratedCode.Markers = map[string][]string{"Go": {"re:("}}
+	if err := cfg.validateGeneratedCode(); err == nil {
+		t.Errorf("validateGeneratedCode with marker %q = nil error, want error", "re:(")
+	}
+}
@@ -0,0 +1,62 @@
+// indentation.go - Tabs and leading / trailing whitespace in the Word output
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ✅ Cách hiển thị ký tự tab
+const (
+	TabsExpand  = "expand"  // Đổi tab thành khoảng trắng tới tab stop kế tiếp (khi scan)
+	TabsTabStop = "tabstop" // Giữ tab, render bằng tab stop của Word theo lưới ký tự
+)
+
+var tabModes = []string{TabsExpand, TabsTabStop}
+
+// Indentation là cấu hình giữ thụt lề của code trong file Word.
+type Indentation struct {
+	Tabs                   string
+	TabWidth               int  // Số cột giữa hai tab stop
+	TrimTrailingWhitespace bool // Bỏ khoảng trắng cuối dòng (không ảnh hưởng thụt lề)
+}
+
+// ✅ IndentationConfig là phần indentation khai báo trong file config.
+type IndentationConfig struct {
+	Tabs                   string `yaml:"tabs,omitempty" json:"tabs,omitempty"`
+	TabWidth               *int   `yaml:"tab_width,omitempty" json:"tab_width,omitempty"`
+	TrimTrailingWhitespace *bool  `yaml:"trim_trailing_whitespace,omitempty" json:"trim_trailing_whitespace,omitempty"`
+}
+
+func defaultIndentation() Indentation {
+	return Indentation{Tabs: TabsExpand, TabWidth: 4}
+}
+
+// ApplyTo ghi đè các field đã khai báo.
+func (ic *IndentationConfig) ApplyTo(indentation *Indentation) {
+	if ic.Tabs != "" {
+		indentation.Tabs = ic.Tabs
+	}
+	if ic.TabWidth != nil {
+		indentation.TabWidth = *ic.TabWidth
+	}
+	if ic.TrimTrailingWhitespace != nil {
+		indentation.TrimTrailingWhitespace = *ic.TrimTrailingWhitespace
+	}
+}
+
+func (i *Indentation) toFileConfig() *IndentationConfig {
+	tabWidth, trim := i.TabWidth, i.TrimTrailingWhitespace
+	return &IndentationConfig{Tabs: i.Tabs, TabWidth: &tabWidth, TrimTrailingWhitespace: &trim}
+}
+
+func (i *Indentation) validate() error {
+	if !containsString(tabModes, i.Tabs) {
+		return fmt.Errorf("invalid config key %q: unknown mode %q (available: %s)",
+			"indentation.tabs", i.Tabs, strings.Join(tabModes, ", "))
+	}
+	if i.TabWidth < 1 || i.TabWidth > 16 {
+		return fmt.Errorf("invalid config key %q: must be between 1 and 16 (got %d)", "indentation.tab_width", i.TabWidth)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// keywords.go - Keyword lists of the built-in languages (used by syntax highlighting)
+package config
+
+var (
+	cFamilyTypes = []string{"void", "char", "short", "int", "long", "float", "double", "signed", "unsigned", "bool"}
+
+	csharpKeywords = []string{
+		"abstract", "as", "async", "await", "base", "bool", "break", "byte", "case", "catch", "char", "checked",
+		"class", "const", "continue", "decimal", "default", "delegate", "do", "double", "else", "enum", "event",
+		"explicit", "extern", "false", "finally", "fixed", "float", "for", "foreach", "get", "goto", "if",
+		"implicit", "in", "init", "int", "interface", "internal", "is", "lock", "long", "namespace", "new", "null",
+		"object", "operator", "out", "override", "params", "partial", "private", "protected", "public", "readonly",
+		"record", "ref", "return", "sbyte", "sealed", "set", "short", "sizeof", "stackalloc", "static", "string",
+		"struct", "switch", "this", "throw", "true", "try", "typeof", "uint", "ulong", "unchecked", "unsafe",
+		"ushort", "using", "var", "virtual", "void", "volatile", "when", "where", "while", "yield",
+	}
+	dartKeywords = []string{
+		"abstract", "as", "assert", "async", "await", "base", "break", "case", "catch", "class", "const",
+		"continue", "covariant", "default", "deferred", "do", "dynamic", "else", "enum", "export", "extends",
+		"extension", "external", "factory", "false", "final", "finally", "for", "get", "hide", "if", "implements",
+		"import", "in", "interface", "is", "late", "library", "mixin", "new", "null", "on", "operator", "part",
+		"required", "rethrow", "return", "sealed", "set", "show", "static", "super", "switch", "sync", "this",
+		"throw", "true", "try", "typedef", "var", "void", "when", "while", "with", "yield",
+		"int", "double", "num", "bool", "String", "List", "Map", "Set", "Future", "Stream",
+	}
+	goKeywords = []string{
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var", "nil", "true", "false", "iota",
+		"bool", "byte", "error", "float32", "float64", "int", "int8", "int16", "int32", "int64", "rune", "string",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "any",
+	}
+	javaScriptKeywords = []string{
+		"async", "await", "break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete",
+		"do", "else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import", "in",
+		"instanceof", "let", "new", "null", "of", "return", "static", "super", "switch", "this", "throw", "true",
+		"try", "typeof", "undefined", "var", "void", "while", "with", "yield",
+	}
+	typeScriptKeywords = append(append([]string{}, javaScriptKeywords...),
+		"abstract", "any", "as", "boolean", "declare", "enum", "implements", "interface", "keyof", "namespace",
+		"never", "number", "private", "protected", "public", "readonly", "string", "type", "unknown")
+	javaKeywords = []string{
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const", "continue",
+		"default", "do", "double", "else", "enum", "extends", "false", "final", "finally", "float", "for", "if",
+		"implements", "import", "instanceof", "int", "interface", "long", "native", "new", "null", "package",
+		"private", "protected", "public", "record", "return", "short", "static", "super", "switch", "synchronized",
+		"this", "throw", "throws", "transient", "true", "try", "var", "void", "volatile", "while",
+	}
+	kotlinKeywords = []string{
+		"abstract", "as", "break", "by", "catch", "class", "companion", "const", "continue", "data", "do", "else",
+		"enum", "false", "final", "finally", "for", "fun", "if", "import", "in", "init", "inline", "interface",
+		"internal", "is", "lateinit", "null", "object", "open", "override", "package", "private", "protected",
+		"public", "return", "sealed", "super", "suspend", "this", "throw", "true", "try", "typealias", "val",
+		"var", "vararg", "when", "while",
+	}
+	swiftKeywords = []string{
+		"as", "associatedtype", "break", "case", "catch", "class", "continue", "default", "defer", "deinit", "do",
+		"else", "enum", "extension", "fallthrough", "false", "fileprivate", "for", "func", "guard", "if", "import",
+		"in", "init", "inout", "internal", "is", "let", "nil", "open", "operator", "override", "private",
+		"protocol", "public", "repeat", "rethrows", "return", "self", "Self", "static", "struct", "subscript",
+		"super", "switch", "throw", "throws", "true", "try", "typealias", "var", "where", "while",
+	}
+	cKeywords = append(append([]string{}, cFamilyTypes...),
+		"auto", "break", "case", "const", "continue", "default", "do", "else", "enum", "extern", "for", "goto",
+		"if", "inline", "register", "restrict", "return", "sizeof", "static", "struct", "switch", "typedef",
+		"union", "volatile", "while", "NULL")
+	objectiveCKeywords = append(append([]string{}, cKeywords...),
+		"id", "self", "super", "nil", "YES", "NO", "BOOL", "instancetype")
+	cppKeywords = append(append([]string{}, cKeywords...),
+		"auto", "catch", "class", "constexpr", "delete", "explicit", "false", "friend", "namespace", "new",
+		"noexcept", "nullptr", "operator", "override", "private", "protected", "public", "template", "this",
+		"throw", "true", "try", "typename", "using", "virtual")
+	pythonKeywords = []string{
+		"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue", "def",
+		"del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda",
+		"nonlocal", "not", "or", "pass", "raise", "return", "self", "try", "while", "with", "yield",
+	}
+	phpKeywords = []string{
+		"abstract", "array", "as", "break", "case", "catch", "class", "const", "continue", "default", "do", "echo",
+		"else", "elseif", "extends", "false", "final", "finally", "fn", "for", "foreach", "function", "if",
+		"implements", "include", "interface", "namespace", "new", "null", "private", "protected", "public",
+		"require", "require_once", "return", "static", "switch", "throw", "trait", "true", "try", "use", "while",
+	}
+	rubyKeywords = []string{
+		"alias", "and", "begin", "break", "case", "class", "def", "do", "else", "elsif", "end",
+		"ensure", "false", "for", "if", "in", "module", "next", "nil", "not", "or", "redo", "require", "rescue",
+		"retry", "return", "self", "super", "then", "true", "undef", "unless", "until", "when", "while", "yield",
+	}
+	rustKeywords = []string{
+		"as", "async", "await", "break", "const", "continue", "crate", "dyn", "else", "enum", "extern", "false",
+		"fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return",
+		"self", "Self", "static", "struct", "super", "trait", "true", "type", "unsafe", "use", "where", "while",
+		"bool", "char", "i8", "i16", "i32", "i64", "isize", "u8", "u16", "u32", "u64", "usize", "f32", "f64", "str",
+	}
+	sqlKeywords = []string{
+		"add", "all", "alter", "and", "as", "asc", "begin", "between", "by", "case", "create", "database",
+		"declare", "default", "delete", "desc", "distinct", "drop", "else", "end", "exists", "foreign", "from",
+		"function", "group", "having", "if", "in", "index", "inner", "insert", "into", "is", "join", "key",
+		"left", "like", "limit", "not", "null", "on", "or", "order", "outer", "primary", "procedure",
+		"references", "return", "right", "select", "set", "table", "then", "top", "union", "unique", "update",
+		"values", "view", "when", "where", "with",
+		"int", "bigint", "bit", "char", "date", "datetime", "decimal", "float", "nvarchar", "text", "varchar",
+	}
+	visualBasicKeywords = []string{
+		"AddHandler", "And", "AndAlso", "As", "Boolean", "ByRef", "ByVal", "Call", "Case", "Catch", "Class",
+		"Const", "Dim", "Do", "Double", "Each", "Else", "ElseIf", "End", "Enum", "Exit", "False", "Finally",
+		"For", "Friend", "Function", "Get", "Handles", "If", "Implements", "Imports", "In", "Inherits", "Integer",
+		"Interface", "Is", "Loop", "Me", "Module", "MustInherit", "MyBase", "Namespace", "New", "Next", "Not",
+		"Nothing", "Of", "Or", "OrElse", "Overridable", "Overrides", "Private", "Property", "Protected", "Public",
+		"ReadOnly", "Return", "Select", "Set", "Shared", "String", "Structure", "Sub", "Then", "Throw", "To",
+		"True", "Try", "Using", "While", "With",
+	}
+)
@@ -0,0 +1,239 @@
+// languages.go - Language registry: extensions, display name, comment syntax, generated-file markers
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Language mô tả một ngôn ngữ được hỗ trợ.
+type Language struct {
+	Name              string   `yaml:"name" json:"name"`                                                 // Tên hiển thị: "C#", "Dart"...
+	Extensions        []string `yaml:"extensions" json:"extensions"`                                     // ".cs", ".dart"...
+	LineComment       string   `yaml:"line_comment,omitempty" json:"line_comment,omitempty"`             // "//", "#"...
+	BlockComment      []string `yaml:"block_comment,omitempty" json:"block_comment,omitempty"`           // ["/*", "*/"]
+	GeneratedSuffixes []string `yaml:"generated_suffixes,omitempty" json:"generated_suffixes,omitempty"` // ".g.dart"...
+	GeneratedMarkers  []string `yaml:"generated_markers,omitempty" json:"generated_markers,omitempty"`   // Chuỗi trong comment ở đầu file sinh tự động, "re:" = regex trên cả dòng
+	// ✅ Dùng cho syntax highlighting
+	Keywords           []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	KeywordsIgnoreCase bool     `yaml:"keywords_ignore_case,omitempty" json:"keywords_ignore_case,omitempty"` // SQL, VB
+	Strings            []string `yaml:"strings,omitempty" json:"strings,omitempty"`                           // Dấu mở/đóng chuỗi, mặc định ["\"", "'"]
+}
+
+// ✅ Ngôn ngữ có sẵn. Chỉ extension nằm trong SupportedExtensions mới được scan.
+var builtinLanguages = []Language{
+	{Name: "C#", Extensions: []string{".cs"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedSuffixes: []string{".designer.cs", ".g.cs", ".g.i.cs", ".generated.cs"},
+		GeneratedMarkers: []string{
+			"<auto-generated", "<autogenerated", "This code was generated by a tool",
+			"Generated by the protocol buffer compiler",
+			`re:^using Microsoft\.EntityFrameworkCore\.Migrations;\s*$`, // EF Core migration
+		},
+		Keywords: csharpKeywords},
+	{Name: "Dart", Extensions: []string{".dart"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedSuffixes: []string{".g.dart", ".freezed.dart", ".gr.dart", ".config.dart", ".pb.dart", ".pbenum.dart", ".pbgrpc.dart", ".pbjson.dart"},
+		GeneratedMarkers:  []string{"GENERATED CODE - DO NOT MODIFY BY HAND", "Generated code. Do not modify"},
+		Keywords:          dartKeywords, Strings: []string{`"""`, "'''", `"`, "'"}},
+	{Name: "Go", Extensions: []string{".go"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedSuffixes: []string{".pb.go"},
+		GeneratedMarkers:  []string{`re:^// Code generated .* DO NOT EDIT\.$`}, // https://go.dev/s/generatedcode
+		Keywords:          goKeywords, Strings: []string{`"`, "`", "'"}},
+	{Name: "TypeScript", Extensions: []string{".ts", ".tsx"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"@generated", "This file is auto-generated", "This file was auto-generated"},
+		Keywords:         typeScriptKeywords, Strings: []string{`"`, "'", "`"}},
+	{Name: "JavaScript", Extensions: []string{".js", ".jsx", ".mjs"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedSuffixes: []string{".min.js"},
+		GeneratedMarkers:  []string{"@generated", "This file is auto-generated", "This file was auto-generated"},
+		Keywords:          javaScriptKeywords, Strings: []string{`"`, "'", "`"}},
+	{Name: "Java", Extensions: []string{".java"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{`re:^\s*@(javax\.annotation\.(processing\.)?)?Generated\b`, "Generated by the protocol buffer compiler"},
+		Keywords:         javaKeywords, Strings: []string{`"""`, `"`, "'"}},
+	{Name: "Kotlin", Extensions: []string{".kt", ".kts"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{`re:^\s*@(javax\.annotation\.(processing\.)?)?Generated\b`, "@generated"},
+		Keywords:         kotlinKeywords, Strings: []string{`"""`, `"`, "'"}},
+	{Name: "Swift", Extensions: []string{".swift"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"Generated by the Swift generator plugin for the protocol buffer compiler", "Generated using SwiftGen", "@generated"},
+		Keywords:         swiftKeywords, Strings: []string{`"""`, `"`}},
+	{Name: "Objective-C", Extensions: []string{".m", ".mm"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"Generated by the protocol buffer compiler"},
+		Keywords:         objectiveCKeywords},
+	{Name: "C", Extensions: []string{".c", ".h"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"Generated by the protocol buffer compiler", "@generated"},
+		Keywords:         cKeywords},
+	{Name: "C++", Extensions: []string{".cpp", ".cc", ".cxx", ".hpp", ".hh"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedSuffixes: []string{".pb.cc", ".pb.h"},
+		GeneratedMarkers:  []string{"Generated by the protocol buffer compiler", "@generated"},
+		Keywords:          cppKeywords},
+	{Name: "Python", Extensions: []string{".py"}, LineComment: "#", BlockComment: []string{`"""`, `"""`},
+		GeneratedSuffixes: []string{"_pb2.py", "_pb2_grpc.py"},
+		GeneratedMarkers:  []string{"Generated by the protocol buffer compiler", "@generated"},
+		Keywords:          pythonKeywords, Strings: []string{"'''", `"`, "'"}},
+	{Name: "PHP", Extensions: []string{".php"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"@generated", "This file is auto-generated", "This file was auto-generated"},
+		Keywords:         phpKeywords},
+	{Name: "Ruby", Extensions: []string{".rb"}, LineComment: "#", BlockComment: []string{"=begin", "=end"},
+		GeneratedMarkers: []string{"Generated by the protocol buffer compiler", "@generated"},
+		Keywords:         rubyKeywords},
+	{Name: "Rust", Extensions: []string{".rs"}, LineComment: "//", BlockComment: []string{"/*", "*/"},
+		GeneratedMarkers: []string{"@generated", "automatically generated by"},
+		Keywords:         rustKeywords, Strings: []string{`"`}},
+	{Name: "SQL", Extensions: []string{".sql"}, LineComment: "--", BlockComment: []string{"/*", "*/"},
+		Keywords: sqlKeywords, Strings: []string{"'"}, KeywordsIgnoreCase: true},
+	{Name: "Visual Basic", Extensions: []string{".vb"}, LineComment: "'",
+		GeneratedMarkers: []string{"<auto-generated"},
+		Keywords:         visualBasicKeywords, Strings: []string{`"`}, KeywordsIgnoreCase: true},
+}
+
+// LanguageRegistry tra cứu ngôn ngữ theo extension; đăng ký sau ghi đè đăng ký trước.
+type LanguageRegistry struct {
+	languages []Language
+	byExt     map[string]int
+	custom    []Language // Đăng ký từ file config (dùng cho `config print`)
+}
+
+// NewLanguageRegistry tạo registry chứa các ngôn ngữ có sẵn.
+func NewLanguageRegistry() *LanguageRegistry {
+	r := &LanguageRegistry{byExt: make(map[string]int)}
+	for _, lang := range builtinLanguages {
+		r.add(lang)
+	}
+	return r
+}
+
+func (r *LanguageRegistry) add(lang Language) {
+	lang.Extensions = normalizeExtensions(lang.Extensions)
+	r.languages = append(r.languages, lang)
+	for _, ext := range lang.Extensions {
+		r.byExt[ext] = len(r.languages) - 1
+	}
+}
+
+// Register thêm (hoặc thay thế theo extension) một ngôn ngữ khai báo trong file config.
+// Giá trị được kiểm tra trong Config.Validate.
+func (r *LanguageRegistry) Register(lang Language) {
+	r.add(lang)
+	r.custom = append(r.custom, r.languages[len(r.languages)-1])
+}
+
+// validate kiểm tra các ngôn ngữ đăng ký từ file config.
+func (r *LanguageRegistry) validate() error {
+	for i, lang := range r.custom {
+		if strings.TrimSpace(lang.Name) == "" {
+			return fmt.Errorf("invalid config key %q: entry %d has no name", "languages", i)
+		}
+		if len(lang.Extensions) == 0 {
+			return fmt.Errorf("invalid config key %q: language %q has no extensions", "languages", lang.Name)
+		}
+		for _, ext := range lang.Extensions {
+			if len(ext) < 2 {
+				return fmt.Errorf("invalid config key %q: language %q has an invalid extension %q", "languages", lang.Name, ext)
+			}
+		}
+		if len(lang.BlockComment) != 0 && len(lang.BlockComment) != 2 {
+			return fmt.Errorf("invalid config key %q: language %q block_comment must be [start, end]", "languages", lang.Name)
+		}
+		for _, delimiter := range lang.Strings {
+			if delimiter == "" {
+				return fmt.Errorf("invalid config key %q: language %q has an empty string delimiter", "languages", lang.Name)
+			}
+		}
+		if err := checkGeneratedMarkers(lang.GeneratedMarkers); err != nil {
+			return fmt.Errorf("invalid config key %q: language %q %v", "languages", lang.Name, err)
+		}
+	}
+	return nil
+}
+
+// Lookup trả về ngôn ngữ của extension (".cs"), ok = false nếu chưa đăng ký.
+func (r *LanguageRegistry) Lookup(ext string) (Language, bool) {
+	idx, ok := r.byExt[strings.ToLower(ext)]
+	if !ok {
+		return Language{}, false
+	}
+	return r.languages[idx], true
+}
+
+// ForExtension luôn trả về một Language: extension chưa đăng ký dùng tên viết hoa của extension.
+func (r *LanguageRegistry) ForExtension(ext string) Language {
+	if lang, ok := r.Lookup(ext); ok {
+		return lang
+	}
+	return Language{Name: strings.ToUpper(strings.TrimPrefix(ext, ".")), Extensions: []string{ext}}
+}
+
+// ByName tìm ngôn ngữ theo tên hiển thị (không phân biệt hoa thường).
+func (r *LanguageRegistry) ByName(name string) (Language, bool) {
+	for i := len(r.languages) - 1; i >= 0; i-- {
+		if strings.EqualFold(r.languages[i].Name, name) {
+			return r.languages[i], true
+		}
+	}
+	return Language{}, false
+}
+
+// Custom trả về các ngôn ngữ đã đăng ký từ file config.
+func (r *LanguageRegistry) Custom() []Language {
+	return r.custom
+}
+
+// Describe trả về "C# (.cs), Dart (.dart)" cho các extension đang bật, theo tên ngôn ngữ.
+func (r *LanguageRegistry) Describe(extensions map[string]bool) string {
+	byName := make(map[string][]string)
+	for ext, enabled := range extensions {
+		if enabled {
+			name := r.ForExtension(ext).Name
+			byName[name] = append(byName[name], ext)
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		exts := byName[name]
+		sort.Strings(exts)
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, strings.Join(exts, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BuiltinLanguages trả về danh sách ngôn ngữ có sẵn (dùng cho help).
+func BuiltinLanguages() []Language {
+	return builtinLanguages
+}
+
+func normalizeExtensions(extensions []string) []string {
+	normalized := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext != "" && !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized = append(normalized, ext)
+	}
+	return normalized
+}
+
+// ✅ Ngôn ngữ của file theo extension
+func (c *Config) LanguageFor(ext string) Language {
+	return c.Languages.ForExtension(ext)
+}
+
+// generatedSuffixFor trả về suffix sinh tự động mà filename (lowercase) khớp, "" nếu không có.
+func (c *Config) generatedSuffixFor(lowerFilename string) string {
+	ext := lowerFilename
+	if idx := strings.LastIndex(lowerFilename, "."); idx >= 0 {
+		ext = lowerFilename[idx:]
+	}
+	for _, suffix := range c.LanguageFor(ext).GeneratedSuffixes {
+		if strings.HasSuffix(lowerFilename, strings.ToLower(suffix)) {
+			return suffix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+// layout.go - Page layout (margins, fonts, line heights) used by both the generator and the paginator
+package config
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ✅ Font của phần code trong file Word
+const (
+	CodeFontFamily = "Consolas"
+	// Cột số dòng "%4d │ " đứng trước mỗi dòng code
+	LineNumberColumns = 7
+	// Độ rộng một ký tự Consolas (em), lề trang mặc định của Word (1 inch mỗi bên)
+	monospaceCharWidthEm = 0.55
+	defaultMarginMM      = 25.4
+	pointMM              = 25.4 / 72
+	// Wrap hẹp hơn mức này thì gần như mỗi ký tự một dòng: coi là cấu hình sai
+	minWrapWidth = 20
+)
+
+// ✅ Cỡ chữ và chiều cao dòng (exact) của các đoạn không phải code, khoảng cách footer tới mép giấy
+const (
+	HeaderFontSize      = 11
+	SeparatorFontSize   = 8
+	TextLineSpacing     = 1.2
+	FooterDistanceMM    = 12.7
+	minMarginMM         = 20 // Footer nằm trong lề dưới
+	maxMarginMM         = 50
+	maxLineSpacing      = 3.0
+	layoutEpsilonPoints = 0.01
+)
+
+// ✅ Tô màu cú pháp cho code
+const (
+	HighlightColor = "color" // Màu cho keyword, chuỗi, comment, số
+	HighlightMono  = "mono"  // Đen trắng khi in: đậm / nghiêng / xám
+	HighlightOff   = "off"
+)
+
+var HighlightThemes = []string{HighlightColor, HighlightMono, HighlightOff}
+
+// ✅ Mục lục các file với trang bắt đầu
+const (
+	TOCOff    = "off"
+	TOCField  = "field"  // Field TOC của Word, số trang được Word cập nhật khi mở file
+	TOCStatic = "static" // Bảng tính sẵn từ paginator
+)
+
+var TOCModes = []string{TOCOff, TOCField, TOCStatic}
+
+// ✅ Cách ngắt trang của phần code
+const (
+	PageBreaksFlow  = "flow"  // Word tự ngắt trang (chỉ có smart page break trước file)
+	PageBreaksFixed = "fixed" // Ngắt trang cứng sau mỗi lines_per_page hàng, trang Word = trang của paginator
+)
+
+var PageBreakModes = []string{PageBreaksFlow, PageBreaksFixed}
+
+// ✅ Bản đồ trang (trang → file, dòng đầu, dòng cuối) ghi cạnh mỗi file .docx
+const (
+	PageMapOff  = "off"
+	PageMapCSV  = "csv"
+	PageMapJSON = "json"
+	PageMapBoth = "both"
+)
+
+var PageMapFormats = []string{PageMapOff, PageMapCSV, PageMapJSON, PageMapBoth}
+
+// CodeWrapWidth trả về số ký tự tối đa của một hàng code (không tính cột số dòng).
+// WrapWidth = 0 thì tự tính từ khổ giấy và cỡ chữ code.
+func (c *Config) CodeWrapWidth() int {
+	if c.WrapWidth > 0 {
+		return c.WrapWidth
+	}
+
+	pageSize, ok := PageSizes[c.PageSize]
+	if !ok || c.CodeFontSize <= 0 {
+		return 0
+	}
+	usableMM := pageSize.WidthMM - 2*c.MarginMM
+	charMM := float64(c.CodeFontSize) * monospaceCharWidthEm * pointMM
+	return int(usableMM/charMM) - LineNumberColumns
+}
+
+// CodeCharWidthPoints trả về độ rộng một ký tự code (pt), dùng để đặt tab stop theo lưới ký tự.
+func (c *Config) CodeCharWidthPoints() float64 {
+	return float64(c.CodeFontSize) * monospaceCharWidthEm
+}
+
+// UsableHeightPoints trả về chiều cao vùng nội dung của trang (pt), đã trừ lề trên và dưới.
+func (c *Config) UsableHeightPoints() float64 {
+	pageSize, ok := PageSizes[c.PageSize]
+	if !ok {
+		return 0
+	}
+	return (pageSize.HeightMM - 2*c.MarginMM) / pointMM
+}
+
+// CodeLineHeight trả về chiều cao (exact) của một hàng code (pt), làm tròn xuống 1/20pt như Word lưu (twip).
+// LineSpacing = 0 thì chia đều vùng nội dung cho lines_per_page hàng.
+func (c *Config) CodeLineHeight() float64 {
+	height := float64(c.CodeFontSize)
+	switch {
+	case c.LineSpacing > 0:
+		height *= c.LineSpacing
+	case c.LinesPerPage > 0:
+		height = c.UsableHeightPoints() / float64(c.LinesPerPage)
+	}
+	return math.Floor(height*20) / 20
+}
+
+// FixedLayout cho biết generator có ngắt trang cứng theo mô hình trang của paginator không.
+func (c *Config) FixedLayout() bool {
+	return c.PageBreaks == PageBreaksFixed
+}
+
+// HeaderLineHeight / SeparatorLineHeight là chiều cao (exact) của header file và dòng phân cách (pt).
+func (c *Config) HeaderLineHeight() float64 {
+	return HeaderFontSize * TextLineSpacing
+}
+
+func (c *Config) SeparatorLineHeight() float64 {
+	return SeparatorFontSize * TextLineSpacing
+}
+
+// RowsPerPage trả về số hàng code vừa một trang theo layout (bằng lines_per_page khi line_spacing = 0).
+func (c *Config) RowsPerPage() int {
+	height := c.CodeLineHeight()
+	if height <= 0 {
+		return c.LinesPerPage
+	}
+	return max(1, int((c.UsableHeightPoints()+layoutEpsilonPoints)/height))
+}
+
+func (c *Config) validateLayout() error {
+	if c.MarginMM < minMarginMM || c.MarginMM > maxMarginMM {
+		return fmt.Errorf("invalid config key %q: must be between %d and %d mm (got %g)", "margin_mm", minMarginMM, maxMarginMM, c.MarginMM)
+	}
+	if c.LineSpacing != 0 && (c.LineSpacing < 1 || c.LineSpacing > maxLineSpacing) {
+		return fmt.Errorf("invalid config key %q: must be 0 (auto) or between 1 and %g (got %g)", "line_spacing", maxLineSpacing, c.LineSpacing)
+	}
+	if c.CodeFontSize < 6 || c.CodeFontSize > 20 {
+		return fmt.Errorf("invalid config key %q: must be between 6 and 20 points (got %d)", "code_font_size", c.CodeFontSize)
+	}
+	if !containsString(HighlightThemes, c.Highlight) {
+		return fmt.Errorf("invalid config key %q: unknown theme %q (available: %s)",
+			"highlight", c.Highlight, strings.Join(HighlightThemes, ", "))
+	}
+	if !containsString(TOCModes, c.TableOfContents) {
+		return fmt.Errorf("invalid config key %q: unknown mode %q (available: %s)",
+			"table_of_contents", c.TableOfContents, strings.Join(TOCModes, ", "))
+	}
+	// ✅ Hàng code thấp hơn cỡ chữ thì Word cắt mất chữ
+	if c.LineSpacing == 0 && c.CodeLineHeight() < float64(c.CodeFontSize) {
+		return fmt.Errorf("invalid config key %q: %d lines of %dpt code do not fit on %s with %gmm margins (at most %d)",
+			"lines_per_page", c.LinesPerPage, c.CodeFontSize, c.PageSize, c.MarginMM,
+			int(c.UsableHeightPoints()/float64(c.CodeFontSize)))
+	}
+	if !containsString(PageBreakModes, c.PageBreaks) {
+		return fmt.Errorf("invalid config key %q: unknown mode %q (available: %s)",
+			"page_breaks", c.PageBreaks, strings.Join(PageBreakModes, ", "))
+	}
+	// Header / separator chiếm đúng compact_header_lines / file_separator_lines hàng ở chế độ fixed
+	// (đoạn cao 0pt thì Word dùng chiều cao mặc định, lệch với mô hình trang)
+	if c.FixedLayout() && c.CompactHeaderLines < 1 {
+		return fmt.Errorf("invalid config key %q: must be at least 1 with page_breaks: fixed (got %d)",
+			"compact_header_lines", c.CompactHeaderLines)
+	}
+	if c.FixedLayout() && c.FileSeparatorLines < 1 {
+		return fmt.Errorf("invalid config key %q: must be at least 1 with page_breaks: fixed (got %d)",
+			"file_separator_lines", c.FileSeparatorLines)
+	}
+	if !containsString(PageMapFormats, c.PageMap) {
+		return fmt.Errorf("invalid config key %q: unknown format %q (available: %s)",
+			"page_map", c.PageMap, strings.Join(PageMapFormats, ", "))
+	}
+	if c.WrapWidth < 0 {
+		return fmt.Errorf("invalid config key %q: must not be negative (got %d)", "wrap_width", c.WrapWidth)
+	}
+	if width := c.CodeWrapWidth(); width < minWrapWidth {
+		return fmt.Errorf("invalid config key %q: wrap width %d is too narrow (minimum %d)", "wrap_width", width, minWrapWidth)
+	}
+	return nil
+}
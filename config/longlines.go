@@ -0,0 +1,136 @@
+// longlines.go - Policy for very long lines and minified / one-line files
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ✅ Cách xử lý file có dòng dài hơn long_lines.max_length
+const (
+	LongLineWrap     = "wrap"     // Giữ nguyên dòng, khi render sẽ xuống dòng
+	LongLineTruncate = "truncate" // Cắt tại max_length và thêm truncate_marker
+	LongLineExclude  = "exclude"  // Loại file (coi như minified)
+)
+
+var longLineActions = []string{LongLineWrap, LongLineTruncate, LongLineExclude}
+
+// LongLines là cấu hình xử lý dòng dài; độ dài tính theo ký tự (rune), không theo byte.
+type LongLines struct {
+	MaxLength      int
+	Action         string // Áp dụng cho file có dòng dài nhưng không phải minified
+	MinifiedAction string // Áp dụng cho file minified / một dòng
+	TruncateMarker string // "{chars}" được thay bằng số ký tự bị cắt
+	Overrides      []LongLineOverride
+	compiled       []compiledLongLineOverride
+}
+
+// LongLineOverride ép action cho các file khớp glob (kể cả file minified).
+type LongLineOverride struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Action  string `yaml:"action" json:"action"`
+}
+
+type compiledLongLineOverride struct {
+	regex  *regexp.Regexp
+	action string
+}
+
+// ✅ LongLinesConfig là phần long_lines khai báo trong file config.
+type LongLinesConfig struct {
+	MaxLength      *int               `yaml:"max_length,omitempty" json:"max_length,omitempty"`
+	Action         string             `yaml:"action,omitempty" json:"action,omitempty"`
+	MinifiedAction string             `yaml:"minified_action,omitempty" json:"minified_action,omitempty"`
+	TruncateMarker *string            `yaml:"truncate_marker,omitempty" json:"truncate_marker,omitempty"`
+	Overrides      []LongLineOverride `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+func defaultLongLines() LongLines {
+	return LongLines{
+		MaxLength:      500,
+		Action:         LongLineWrap,
+		MinifiedAction: LongLineExclude,
+		TruncateMarker: " … [+{chars} chars]",
+	}
+}
+
+// ApplyTo ghi đè các field đã khai báo (overrides thay thế hoàn toàn).
+func (lc *LongLinesConfig) ApplyTo(longLines *LongLines) {
+	if lc.MaxLength != nil {
+		longLines.MaxLength = *lc.MaxLength
+	}
+	if lc.Action != "" {
+		longLines.Action = lc.Action
+	}
+	if lc.MinifiedAction != "" {
+		longLines.MinifiedAction = lc.MinifiedAction
+	}
+	if lc.TruncateMarker != nil {
+		longLines.TruncateMarker = *lc.TruncateMarker
+	}
+	if lc.Overrides != nil {
+		longLines.Overrides = append([]LongLineOverride{}, lc.Overrides...)
+		longLines.compiled = nil
+	}
+}
+
+func (l *LongLines) toFileConfig() *LongLinesConfig {
+	maxLength, marker := l.MaxLength, l.TruncateMarker
+	return &LongLinesConfig{
+		MaxLength:      &maxLength,
+		Action:         l.Action,
+		MinifiedAction: l.MinifiedAction,
+		TruncateMarker: &marker,
+		Overrides:      append([]LongLineOverride{}, l.Overrides...),
+	}
+}
+
+func (l *LongLines) validate() error {
+	if l.MaxLength <= 0 {
+		return fmt.Errorf("invalid config key %q: must be greater than 0 (got %d)", "long_lines.max_length", l.MaxLength)
+	}
+	if !containsString(longLineActions, l.Action) {
+		return fmt.Errorf("invalid config key %q: unknown action %q (available: %s)",
+			"long_lines.action", l.Action, strings.Join(longLineActions, ", "))
+	}
+	if !containsString(longLineActions, l.MinifiedAction) {
+		return fmt.Errorf("invalid config key %q: unknown action %q (available: %s)",
+			"long_lines.minified_action", l.MinifiedAction, strings.Join(longLineActions, ", "))
+	}
+
+	compiled := make([]compiledLongLineOverride, 0, len(l.Overrides))
+	for _, override := range l.Overrides {
+		if !containsString(longLineActions, override.Action) {
+			return fmt.Errorf("invalid config key %q: unknown action %q for %q (available: %s)",
+				"long_lines.overrides", override.Action, override.Pattern, strings.Join(longLineActions, ", "))
+		}
+		regex, err := CompileGlob(strings.TrimSpace(override.Pattern), true)
+		if err != nil || strings.TrimSpace(override.Pattern) == "" {
+			return fmt.Errorf("invalid config key %q: invalid pattern %q", "long_lines.overrides", override.Pattern)
+		}
+		compiled = append(compiled, compiledLongLineOverride{regex: regex, action: override.Action})
+	}
+	l.compiled = compiled
+	return nil
+}
+
+// LongLineActionFor trả về action cho file có dòng dài: override khớp cuối cùng,
+// rồi tới MinifiedAction (file minified) hoặc Action.
+func (c *Config) LongLineActionFor(relPath string, minified bool) string {
+	if c.LongLines.compiled == nil && len(c.LongLines.Overrides) > 0 {
+		if err := c.LongLines.validate(); err != nil {
+			fmt.Fprintf(c.Out(), "⚠️  %v\n", err)
+		}
+	}
+
+	for i := len(c.LongLines.compiled) - 1; i >= 0; i-- {
+		if c.LongLines.compiled[i].regex.MatchString(relPath) {
+			return c.LongLines.compiled[i].action
+		}
+	}
+	if minified {
+		return c.LongLines.MinifiedAction
+	}
+	return c.LongLines.Action
+}
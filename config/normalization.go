@@ -0,0 +1,59 @@
+// normalization.go - Line endings, control characters and binary detection settings
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ✅ Cách xử lý ký tự điều khiển không hợp lệ trong OOXML (NUL, form feed, ESC...)
+const (
+	ControlCharsEscape = "escape" // Ghi dạng \x0C để người đọc thấy
+	ControlCharsStrip  = "strip"  // Bỏ hẳn
+)
+
+var controlCharsActions = []string{ControlCharsEscape, ControlCharsStrip}
+
+// Normalization là cấu hình chuẩn hoá nội dung file trước khi render.
+// Xuống dòng CRLF / CR luôn được chuẩn hoá thành LF.
+type Normalization struct {
+	ControlChars    string
+	BinaryThreshold float64 // Tỉ lệ ký tự điều khiển để coi là file nhị phân (0-1)
+}
+
+// ✅ NormalizationConfig là phần normalization khai báo trong file config.
+type NormalizationConfig struct {
+	ControlChars    string   `yaml:"control_chars,omitempty" json:"control_chars,omitempty"`
+	BinaryThreshold *float64 `yaml:"binary_threshold,omitempty" json:"binary_threshold,omitempty"`
+}
+
+func defaultNormalization() Normalization {
+	return Normalization{ControlChars: ControlCharsEscape, BinaryThreshold: 0.1}
+}
+
+// ApplyTo ghi đè các field đã khai báo.
+func (nc *NormalizationConfig) ApplyTo(normalization *Normalization) {
+	if nc.ControlChars != "" {
+		normalization.ControlChars = nc.ControlChars
+	}
+	if nc.BinaryThreshold != nil {
+		normalization.BinaryThreshold = *nc.BinaryThreshold
+	}
+}
+
+func (n *Normalization) toFileConfig() *NormalizationConfig {
+	threshold := n.BinaryThreshold
+	return &NormalizationConfig{ControlChars: n.ControlChars, BinaryThreshold: &threshold}
+}
+
+func (n *Normalization) validate() error {
+	if !containsString(controlCharsActions, n.ControlChars) {
+		return fmt.Errorf("invalid config key %q: unknown action %q (available: %s)",
+			"normalization.control_chars", n.ControlChars, strings.Join(controlCharsActions, ", "))
+	}
+	if n.BinaryThreshold <= 0 || n.BinaryThreshold > 1 {
+		return fmt.Errorf("invalid config key %q: must be greater than 0 and at most 1 (got %g)",
+			"normalization.binary_threshold", n.BinaryThreshold)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// ordering.go - Order of files in the full and shortened documents
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ✅ Chiến lược sắp xếp file
+const (
+	OrderPath        = "path"         // Theo cấu trúc thư mục (mặc định)
+	OrderDepth       = "depth"        // Thư mục nông trước, rồi theo đường dẫn
+	OrderPriority    = "priority"     // Theo danh sách glob ordering.priority, file không khớp xếp cuối
+	OrderLanguage    = "language"     // Gom theo ngôn ngữ (tên A-Z), rồi theo đường dẫn
+	OrderSize        = "size"         // File nhiều dòng trước
+	OrderEntryPoints = "entry-points" // File khớp ordering.entry_points trước (main, Program.cs...), rồi theo đường dẫn
+)
+
+var OrderStrategies = []string{OrderPath, OrderDepth, OrderPriority, OrderLanguage, OrderSize, OrderEntryPoints}
+
+// Ordering là cấu hình thứ tự file; thứ tự này được dùng cho cả file đầy đủ lẫn file rút gọn.
+type Ordering struct {
+	Strategy    string
+	Priority    []string // Glob theo thứ tự ưu tiên; đường dẫn cụ thể (lib/main.dart) cũng là glob
+	EntryPoints []string // Glob của file entry point
+	compiled    *compiledOrdering
+}
+
+type compiledOrdering struct {
+	priority    []*regexp.Regexp
+	entryPoints []*regexp.Regexp
+}
+
+// ✅ OrderingConfig là phần ordering khai báo trong file config (list khai báo thay thế hoàn toàn).
+type OrderingConfig struct {
+	Strategy    string   `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	Priority    []string `yaml:"priority,omitempty" json:"priority,omitempty"`
+	EntryPoints []string `yaml:"entry_points,omitempty" json:"entry_points,omitempty"`
+}
+
+func defaultOrdering() Ordering {
+	return Ordering{
+		Strategy: OrderPath,
+		EntryPoints: []string{
+			"main.dart", "main.go", "Program.cs", "Startup.cs", "App.xaml.cs",
+			"main.ts", "main.js", "index.ts", "index.js", "app.ts", "app.js",
+			"Main.java", "Application.java", "MainActivity.kt", "Application.kt",
+			"AppDelegate.swift", "App.swift", "main.swift", "main.m",
+			"main.c", "main.cpp", "__main__.py", "main.py", "app.py", "manage.py",
+			"index.php", "main.rs", "lib.rs",
+		},
+	}
+}
+
+// ApplyTo ghi đè các field đã khai báo.
+func (oc *OrderingConfig) ApplyTo(ordering *Ordering) {
+	if oc.Strategy != "" {
+		ordering.Strategy = oc.Strategy
+	}
+	if oc.Priority != nil {
+		ordering.Priority = append([]string{}, oc.Priority...)
+		ordering.compiled = nil
+	}
+	if oc.EntryPoints != nil {
+		ordering.EntryPoints = append([]string{}, oc.EntryPoints...)
+		ordering.compiled = nil
+	}
+}
+
+func (o *Ordering) toFileConfig() *OrderingConfig {
+	return &OrderingConfig{
+		Strategy:    o.Strategy,
+		Priority:    append([]string{}, o.Priority...),
+		EntryPoints: append([]string{}, o.EntryPoints...),
+	}
+}
+
+func (o *Ordering) validate() error {
+	if !containsString(OrderStrategies, o.Strategy) {
+		return fmt.Errorf("invalid config key %q: unknown strategy %q (available: %s)",
+			"ordering.strategy", o.Strategy, strings.Join(OrderStrategies, ", "))
+	}
+	if o.Strategy == OrderPriority && len(o.Priority) == 0 {
+		return fmt.Errorf("invalid config key %q: strategy %q needs at least one pattern", "ordering.priority", OrderPriority)
+	}
+
+	compiled := &compiledOrdering{}
+	lists := []struct {
+		key      string
+		patterns []string
+		target   *[]*regexp.Regexp
+	}{
+		{"ordering.priority", o.Priority, &compiled.priority},
+		{"ordering.entry_points", o.EntryPoints, &compiled.entryPoints},
+	}
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			regex, err := CompileGlob(strings.TrimSpace(pattern), true)
+			if err != nil {
+				return fmt.Errorf("invalid config key %q: invalid pattern %q", list.key, pattern)
+			}
+			*list.target = append(*list.target, regex)
+		}
+	}
+	o.compiled = compiled
+	return nil
+}
+
+// PriorityRank trả về vị trí của glob đầu tiên trong ordering.priority khớp với file,
+// len(Priority) nếu không khớp.
+func (c *Config) PriorityRank(relPath string) int {
+	return firstMatch(c.compiledOrdering().priority, relPath)
+}
+
+// EntryPointRank trả về vị trí của glob đầu tiên trong ordering.entry_points khớp với file,
+// len(EntryPoints) nếu không phải entry point.
+func (c *Config) EntryPointRank(relPath string) int {
+	return firstMatch(c.compiledOrdering().entryPoints, relPath)
+}
+
+func (c *Config) compiledOrdering() *compiledOrdering {
+	if c.Ordering.compiled == nil {
+		if err := c.Ordering.validate(); err != nil {
+			fmt.Fprintf(c.Out(), "⚠️  %v\n", err)
+			return &compiledOrdering{}
+		}
+	}
+	return c.Ordering.compiled
+}
+
+func firstMatch(patterns []*regexp.Regexp, relPath string) int {
+	for i, regex := range patterns {
+		if regex.MatchString(relPath) {
+			return i
+		}
+	}
+	return len(patterns)
+}
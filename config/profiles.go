@@ -0,0 +1,290 @@
+// profiles.go - Named profiles for different registration targets
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ✅ Các chiến lược trích đoạn cho file rút gọn
+const (
+	ExcerptFirstMiddleLast = "first-middle-last" // Đầu + giữa + cuối (mặc định)
+	ExcerptFirstLast       = "first-last"        // Đầu + cuối
+	ExcerptEvenlySpaced    = "evenly-spaced"     // excerpt_sections đoạn cách đều nhau
+	ExcerptCurated         = "curated"           // Các file / khoảng dòng khai báo trong excerpt_ranges
+)
+
+var ExcerptStrategies = []string{ExcerptFirstMiddleLast, ExcerptFirstLast, ExcerptEvenlySpaced, ExcerptCurated}
+
+// ✅ Điểm cắt của các đoạn trích trong file rút gọn
+const (
+	ExcerptBoundaryLines        = "lines"        // Cắt theo hàng (mặc định)
+	ExcerptBoundaryDeclarations = "declarations" // Cắt trước khai báo cấp cao nhất (class, method, hàm)
+	ExcerptBoundaryFiles        = "files"        // Lấy nguyên file, file quá dài thì cắt theo khai báo
+)
+
+var ExcerptBoundaries = []string{ExcerptBoundaryLines, ExcerptBoundaryDeclarations, ExcerptBoundaryFiles}
+
+// ✅ Khổ giấy hỗ trợ (mm)
+type PageSize struct {
+	WidthMM  float64
+	HeightMM float64
+}
+
+var PageSizes = map[string]PageSize{
+	"A4":     {WidthMM: 210, HeightMM: 297},
+	"Letter": {WidthMM: 215.9, HeightMM: 279.4},
+	"Legal":  {WidthMM: 215.9, HeightMM: 355.6},
+}
+
+// ✅ Thông tin trang bìa (được render ở bước tạo document)
+type CoverPage struct {
+	Enabled        bool
+	Language       string // "vi" hoặc "en"
+	Title          string
+	SoftwareName   string
+	Version        string
+	Owner          string
+	Authors        []string
+	CompletionDate string // YYYY-MM-DD
+	SkipPageNumber bool   // Trang bìa không đánh số, trang code đầu tiên là trang 1
+}
+
+// ParseCompletionDate đọc CompletionDate (YYYY-MM-DD); chưa khai báo thì trả về zero time.
+func (cp CoverPage) ParseCompletionDate() (time.Time, error) {
+	if cp.CompletionDate == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", cp.CompletionDate)
+}
+
+// Profile gom các thiết lập theo từng nơi nộp hồ sơ.
+type Profile struct {
+	Name                  string
+	Description           string
+	PageSize              string
+	LinesPerPage          int
+	MinLinesForPageBreak  int
+	ShortenThresholdPages int // Vượt quá số trang này thì tạo thêm file rút gọn (0 = không bao giờ)
+	TargetPages           int
+	ExcerptStrategy       string
+	ExcerptSections       int
+	ExcerptRanges         []ExcerptRange
+	Cover                 CoverPage
+}
+
+// ✅ Profile có sẵn
+var builtinProfiles = map[string]Profile{
+	"vn-cov": {
+		Name:                  "vn-cov",
+		Description:           "Cục Bản quyền tác giả Việt Nam: >100 trang thì nộp 75 trang đầu/giữa/cuối",
+		PageSize:              "A4",
+		LinesPerPage:          70,
+		MinLinesForPageBreak:  45,
+		ShortenThresholdPages: 100,
+		TargetPages:           75,
+		ExcerptStrategy:       ExcerptFirstMiddleLast,
+		Cover: CoverPage{
+			Enabled:        true,
+			Language:       "vi",
+			Title:          "MÃ NGUỒN CHƯƠNG TRÌNH MÁY TÍNH",
+			SkipPageNumber: true,
+		},
+	},
+	"us-co-deposit": {
+		Name:                  "us-co-deposit",
+		Description:           "U.S. Copyright Office deposit: first 25 and last 25 pages",
+		PageSize:              "Letter",
+		LinesPerPage:          60,
+		MinLinesForPageBreak:  40,
+		ShortenThresholdPages: 50,
+		TargetPages:           50,
+		ExcerptStrategy:       ExcerptFirstLast,
+		Cover: CoverPage{
+			Enabled:  true,
+			Language: "en",
+			Title:    "COMPUTER PROGRAM SOURCE CODE DEPOSIT",
+		},
+	},
+	"internal-archive": {
+		Name:                  "internal-archive",
+		Description:           "Internal archive: full document only, no excerpt",
+		PageSize:              "A4",
+		LinesPerPage:          70,
+		MinLinesForPageBreak:  45,
+		ShortenThresholdPages: 0,
+		TargetPages:           75,
+		ExcerptStrategy:       ExcerptFirstMiddleLast,
+		Cover: CoverPage{
+			Language: "en",
+			Title:    "SOURCE CODE ARCHIVE",
+		},
+	},
+}
+
+// DefaultProfile là profile được dùng khi không chỉ định.
+const DefaultProfile = "vn-cov"
+
+// ✅ ProfileConfig là profile khai báo trong file config.
+// Field không khai báo được lấy từ profile `extends` (mặc định: DefaultProfile).
+type ProfileConfig struct {
+	Extends               string         `yaml:"extends,omitempty" json:"extends,omitempty"`
+	Description           *string        `yaml:"description,omitempty" json:"description,omitempty"`
+	PageSize              *string        `yaml:"page_size,omitempty" json:"page_size,omitempty"`
+	LinesPerPage          *int           `yaml:"lines_per_page,omitempty" json:"lines_per_page,omitempty"`
+	MinLinesForPageBreak  *int           `yaml:"min_lines_for_page_break,omitempty" json:"min_lines_for_page_break,omitempty"`
+	ShortenThresholdPages *int           `yaml:"shorten_threshold_pages,omitempty" json:"shorten_threshold_pages,omitempty"`
+	TargetPages           *int           `yaml:"target_pages,omitempty" json:"target_pages,omitempty"`
+	ExcerptStrategy       *string        `yaml:"excerpt_strategy,omitempty" json:"excerpt_strategy,omitempty"`
+	ExcerptSections       *int           `yaml:"excerpt_sections,omitempty" json:"excerpt_sections,omitempty"`
+	ExcerptRanges         []ExcerptRange `yaml:"excerpt_ranges,omitempty" json:"excerpt_ranges,omitempty"`
+	Cover                 *CoverConfig   `yaml:"cover,omitempty" json:"cover,omitempty"`
+}
+
+// ✅ CoverConfig là phần cover khai báo trong file config, chỉ ghi đè field có giá trị.
+type CoverConfig struct {
+	Enabled        *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Language       string   `yaml:"language,omitempty" json:"language,omitempty"`
+	Title          string   `yaml:"title,omitempty" json:"title,omitempty"`
+	SoftwareName   string   `yaml:"software_name,omitempty" json:"software_name,omitempty"`
+	Version        string   `yaml:"version,omitempty" json:"version,omitempty"`
+	Owner          string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Authors        []string `yaml:"authors,omitempty" json:"authors,omitempty"`
+	CompletionDate string   `yaml:"completion_date,omitempty" json:"completion_date,omitempty"`
+	SkipPageNumber *bool    `yaml:"skip_page_number,omitempty" json:"skip_page_number,omitempty"`
+}
+
+// ApplyTo ghi đè các field đã khai báo lên cover.
+func (cc *CoverConfig) ApplyTo(cover *CoverPage) {
+	if cc.Enabled != nil {
+		cover.Enabled = *cc.Enabled
+	}
+	overrides := []struct {
+		value  string
+		target *string
+	}{
+		{cc.Language, &cover.Language},
+		{cc.Title, &cover.Title},
+		{cc.SoftwareName, &cover.SoftwareName},
+		{cc.Version, &cover.Version},
+		{cc.Owner, &cover.Owner},
+		{cc.CompletionDate, &cover.CompletionDate},
+	}
+	for _, o := range overrides {
+		if o.value != "" {
+			*o.target = o.value
+		}
+	}
+	if cc.Authors != nil {
+		cover.Authors = append([]string{}, cc.Authors...)
+	}
+	if cc.SkipPageNumber != nil {
+		cover.SkipPageNumber = *cc.SkipPageNumber
+	}
+}
+
+// ResolveProfile tìm profile theo tên: profile trong file config được ưu tiên hơn profile có sẵn.
+func ResolveProfile(name string, custom map[string]ProfileConfig) (Profile, error) {
+	return resolveProfile(name, custom, map[string]bool{})
+}
+
+func resolveProfile(name string, custom map[string]ProfileConfig, visiting map[string]bool) (Profile, error) {
+	pc, ok := custom[name]
+	if !ok {
+		if builtin, ok := builtinProfiles[name]; ok {
+			return builtin, nil
+		}
+		return Profile{}, fmt.Errorf("unknown profile %q (available: %s)",
+			name, strings.Join(ProfileNames(custom), ", "))
+	}
+
+	if visiting[name] {
+		return Profile{}, fmt.Errorf("profile %q has a cyclic extends chain", name)
+	}
+	visiting[name] = true
+
+	baseName := pc.Extends
+	if baseName == "" {
+		baseName = DefaultProfile
+	}
+	var base Profile
+	if builtin, ok := builtinProfiles[baseName]; ok && baseName == name {
+		// Profile trong file trùng tên profile có sẵn: kế thừa bản có sẵn
+		base = builtin
+	} else {
+		var err error
+		base, err = resolveProfile(baseName, custom, visiting)
+		if err != nil {
+			return Profile{}, fmt.Errorf("profiles.%s.extends: %v", name, err)
+		}
+	}
+
+	profile := base
+	profile.Name = name
+	if pc.Description != nil {
+		profile.Description = *pc.Description
+	}
+	if pc.PageSize != nil {
+		profile.PageSize = *pc.PageSize
+	}
+	if pc.LinesPerPage != nil {
+		profile.LinesPerPage = *pc.LinesPerPage
+	}
+	if pc.MinLinesForPageBreak != nil {
+		profile.MinLinesForPageBreak = *pc.MinLinesForPageBreak
+	}
+	if pc.ShortenThresholdPages != nil {
+		profile.ShortenThresholdPages = *pc.ShortenThresholdPages
+	}
+	if pc.TargetPages != nil {
+		profile.TargetPages = *pc.TargetPages
+	}
+	if pc.ExcerptStrategy != nil {
+		profile.ExcerptStrategy = *pc.ExcerptStrategy
+	}
+	if pc.ExcerptSections != nil {
+		profile.ExcerptSections = *pc.ExcerptSections
+	}
+	if pc.ExcerptRanges != nil {
+		profile.ExcerptRanges = append([]ExcerptRange{}, pc.ExcerptRanges...)
+	}
+	if pc.Cover != nil {
+		pc.Cover.ApplyTo(&profile.Cover)
+	}
+
+	return profile, nil
+}
+
+// ProfileNames trả về tên tất cả profile (có sẵn + trong file), đã sắp xếp.
+func ProfileNames(custom map[string]ProfileConfig) []string {
+	seen := make(map[string]bool)
+	for name := range builtinProfiles {
+		seen[name] = true
+	}
+	for name := range custom {
+		seen[name] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile ghi các thiết lập của profile lên config.
+func (c *Config) ApplyProfile(profile Profile) {
+	c.Profile = profile.Name
+	c.PageSize = profile.PageSize
+	c.LinesPerPage = profile.LinesPerPage
+	c.MinLinesForPageBreak = profile.MinLinesForPageBreak
+	c.ShortenThresholdPages = profile.ShortenThresholdPages
+	c.TargetPages = profile.TargetPages
+	c.ExcerptStrategy = profile.ExcerptStrategy
+	c.ExcerptSections = profile.ExcerptSections
+	c.ExcerptRanges = append([]ExcerptRange{}, profile.ExcerptRanges...)
+	c.Cover = profile.Cover
+}
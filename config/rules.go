@@ -0,0 +1,157 @@
+// rules.go - Path-aware exclusion rules (doublestar globs, anchored regexes, negation)
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ✅ Một rule exclude đã được compile.
+//
+// Cú pháp (giống .gitignore):
+//   - "lib/generated/**"  glob trên đường dẫn tương đối so với thư mục gốc
+//   - "*.g.dart"          glob không có "/" thì khớp tên file ở mọi cấp thư mục
+//   - "/Program.cs"       "/" ở đầu: chỉ khớp ở thư mục gốc
+//   - "re:^src/.*Dto\.cs$" regex (tự động neo ^...$) trên đường dẫn tương đối
+//   - "name:*config*"     glob chỉ xét tên file, không khớp tên thư mục (dùng cho rule mặc định)
+//   - "!lib/keyboard/**"  "!" ở đầu: đưa file trở lại (re-include)
+//
+// Rule được xét theo thứ tự, rule khớp cuối cùng quyết định.
+type ExcludeRule struct {
+	Source   string // Rule gốc như khai báo
+	Negate   bool
+	FileName bool // "name:": chỉ xét tên file
+	regex    *regexp.Regexp
+}
+
+// Matches kiểm tra đường dẫn tương đối (dùng "/").
+func (r ExcludeRule) Matches(relPath string) bool {
+	if r.FileName {
+		return r.regex.MatchString(path.Base(relPath))
+	}
+	return r.regex.MatchString(relPath)
+}
+
+// CompileExcludeRule parse một rule theo cú pháp ở trên.
+func CompileExcludeRule(source string) (ExcludeRule, error) {
+	rule := ExcludeRule{Source: source}
+	pattern := strings.TrimSpace(source)
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.Negate = true
+		pattern = strings.TrimSpace(pattern[1:])
+	}
+	if pattern == "" {
+		return rule, fmt.Errorf("empty rule")
+	}
+
+	if strings.HasPrefix(pattern, "re:") {
+		// Luôn neo trên toàn bộ đường dẫn; ^ / $ người dùng tự viết vẫn hợp lệ bên trong nhóm
+		regex, err := regexp.Compile("^(?:" + strings.TrimPrefix(pattern, "re:") + ")$")
+		if err != nil {
+			return rule, fmt.Errorf("invalid regex: %v", err)
+		}
+		rule.regex = regex
+		return rule, nil
+	}
+
+	if strings.HasPrefix(pattern, "name:") {
+		pattern = strings.TrimPrefix(pattern, "name:")
+		if pattern == "" || strings.Contains(pattern, "/") {
+			return rule, fmt.Errorf("name: rule must be a file name glob without \"/\"")
+		}
+		rule.FileName = true
+	}
+
+	regex, err := CompileGlob(pattern, true)
+	if err != nil {
+		return rule, err
+	}
+	rule.regex = regex
+	return rule, nil
+}
+
+// CompileGlob chuyển glob kiểu .gitignore (hỗ trợ "**") thành regex khớp đường dẫn tương đối.
+// Glob cũng khớp mọi file nằm bên trong thư mục trùng tên.
+func CompileGlob(pattern string, foldCase bool) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty glob")
+	}
+
+	var sb strings.Builder
+	if foldCase {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("^")
+	if !anchored && !strings.Contains(pattern, "/") {
+		// Không có "/" → khớp ở mọi cấp thư mục
+		sb.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch ch {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				atStart := i == 0 || pattern[i-1] == '/'
+				i++
+				if atStart && i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" → không hoặc nhiều thư mục
+					sb.WriteString("(?:.*/)?")
+					i++
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in %q", pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+
+	sb.WriteString("(?:/.*)?$")
+	return regexp.Compile(sb.String())
+}
+
+// compileExcludeRules compile toàn bộ ExcludeRules, lỗi ghi rõ rule nào sai.
+func (c *Config) compileExcludeRules() ([]ExcludeRule, error) {
+	rules := make([]ExcludeRule, 0, len(c.ExcludeRules))
+	for i, source := range c.ExcludeRules {
+		rule, err := CompileExcludeRule(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config key %q: entry %d %q: %v", "exclude_rules", i, source, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ✅ Hàm thêm rule exclude runtime
+func (c *Config) AddExcludeRule(rule string) {
+	c.ExcludeRules = append(c.ExcludeRules, rule)
+	c.compiledRules = nil
+}
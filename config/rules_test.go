@@ -0,0 +1,112 @@
+package config
+
+import "testing"
+
+func TestExcludeRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule string
+		path string
+		want bool
+	}{
+		// Glob không có "/": khớp tên file và cả thư mục trùng tên ở mọi cấp
+		{"*.g.dart", "lib/models/user.g.dart", true},
+		{"*.g.dart", "lib/models/user.dart", false},
+		{"*config*", "src/Configuration/Module0.cs", true},
+		{"generated", "lib/generated/a.dart", true},
+		// "name:": chỉ xét tên file
+		{"name:*config*", "src/Configuration/Module0.cs", false},
+		{"name:*config*", "src/AppConfig.cs", true},
+		{"name:*token*", "src/Tokenizer/Lexer.cs", false},
+		{"name:*Bank*", "lib/banking/Account.dart", false},
+		{"name:*Bank*", "lib/BankAccount.dart", true},
+		// Có "/": neo theo đường dẫn tương đối
+		{"lib/generated/**", "lib/generated/api/a.dart", true},
+		{"lib/generated/**", "src/lib/generated/a.dart", false},
+		{"/Program.cs", "Program.cs", true},
+		{"/Program.cs", "src/Program.cs", false},
+		{"**/Dto/*.cs", "src/Orders/Dto/OrderDto.cs", true},
+		// Không phân biệt hoa thường
+		{"*SECRET*", "src/mysecret.cs", true},
+		// Regex: tự động neo ^...$ trên toàn bộ đường dẫn
+		{`re:src/.*Dto\.cs`, "src/Orders/OrderDto.cs", true},
+		{`re:src/.*Dto\.cs`, "lib/src/OrderDto.cs", false},
+		{`re:^src/.*Dto\.cs$`, "src/OrderDto.cs", true},
+		{`re:Dto`, "src/Dto.cs", false},
+		{`re:price\$`, "price$", true},
+		{`re:price\$`, "price", false},
+		{`re:^src/.*\$$`, "src/a$", true},
+		// "!" chỉ đánh dấu Negate, phần còn lại khớp như thường
+		{"!lib/keyboard/**", "lib/keyboard/Keys.dart", true},
+	}
+
+	for _, tt := range tests {
+		rule, err := CompileExcludeRule(tt.rule)
+		if err != nil {
+			t.Fatalf("CompileExcludeRule(%q): %v", tt.rule, err)
+		}
+		if got := rule.Matches(tt.path); got != tt.want {
+			t.Errorf("rule %q on %q = %v, want %v", tt.rule, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCompileExcludeRuleErrors(t *testing.T) {
+	for _, source := range []string{"", "!", "re:(", "[abc", "name:", "name:src/*.cs"} {
+		if _, err := CompileExcludeRule(source); err == nil {
+			t.Errorf("CompileExcludeRule(%q) = nil error, want error", source)
+		}
+	}
+}
+
+func TestExcludeRulesLastMatchWins(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		path  string
+		want  bool
+	}{
+		{"excluded", []string{"lib/**"}, "lib/a.dart", true},
+		{"negated later", []string{"lib/**", "!lib/keyboard/**"}, "lib/keyboard/Keys.dart", false},
+		{"negation does not affect others", []string{"lib/**", "!lib/keyboard/**"}, "lib/a.dart", true},
+		{"excluded again", []string{"lib/**", "!lib/keyboard/**", "*Test*"}, "lib/keyboard/KeyTest.dart", true},
+		{"negation only", []string{"!lib/**"}, "lib/a.dart", false},
+		{"no match", []string{"lib/**"}, "src/a.cs", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := LoadConfig()
+			cfg.ExcludeRules = tt.rules
+			cfg.compiledRules = nil
+
+			match := cfg.MatchExclusion(tt.path)
+			if match.Excluded != tt.want {
+				t.Errorf("MatchExclusion(%q) = %+v, want excluded %v", tt.path, match, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultRulesSkipDirectories(t *testing.T) {
+	cfg := LoadConfig()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"src/Configuration/Module0.cs", false},
+		{"src/Tokenizer/Lexer.cs", false},
+		{"src/Settings/Theme.cs", false},
+		{"lib/banking/account.dart", false},
+		{"src/BIDV/Client.cs", false},
+		{"src/AppConfig.cs", true},
+		{"src/Settings/UserSettings.cs", true},
+		{"lib/models/user.g.dart", true},
+		{".env", true},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.IsFileExcluded(tt.path); got != tt.want {
+			t.Errorf("IsFileExcluded(%q) = %v, want %v (%+v)", tt.path, got, tt.want, cfg.MatchExclusion(tt.path))
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// decisions.go - Why each file was included or excluded (table, JSON, --explain)
+package fileprocessor
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/gitrepo"
+	"copyright-code-word/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ✅ Lý do quyết định cho từng file / thư mục
+const (
+	ReasonIncluded        = "included"
+	ReasonExtension       = "extension"
+	ReasonDirectorySkip   = "directory-skip"
+	ReasonIgnoreFile      = "ignore-file"
+	ReasonExactName       = config.MatchExactName
+	ReasonPattern         = config.MatchPattern
+	ReasonGeneratedSuffix = config.MatchGeneratedSuffix
+	ReasonRule            = config.MatchRule
+	ReasonGeneratedMarker = "generated-marker"
+	ReasonEmpty           = "empty"
+	ReasonEncoding        = "encoding"
+	ReasonBinary          = "binary"
+	ReasonMinified        = "minified"
+	ReasonLongLines       = "long-lines"
+	ReasonError           = "error"
+)
+
+// Decision ghi lại file/thư mục được giữ hay bị loại, và rule nào đã quyết định.
+type Decision struct {
+	Path     string `json:"path"` // Tương đối so với thư mục gốc; thư mục kết thúc bằng "/"
+	Included bool   `json:"included"`
+	Reason   string `json:"reason"`
+	Rule     string `json:"rule,omitempty"`
+}
+
+// Decisions trả về quyết định của lần scan gần nhất, theo thứ tự duyệt.
+func (fp *FileProcessor) Decisions() []Decision {
+	return fp.decisions
+}
+
+func (fp *FileProcessor) record(decision Decision) {
+	fp.decisions = append(fp.decisions, decision)
+}
+
+// decideDirectory kiểm tra thư mục có bị bỏ qua không (relDir khác "").
+func (fp *FileProcessor) decideDirectory(relDir, name string) (Decision, bool) {
+	if skipDirs[name] {
+		return Decision{Path: relDir + "/", Reason: ReasonDirectorySkip, Rule: "built-in skip list: " + name}, true
+	}
+
+	// ✅ Thư mục bị ignore thì bỏ qua toàn bộ (giống git)
+	if rule := fp.ignore.match(relDir, true); rule != nil {
+		return Decision{Path: relDir + "/", Reason: ReasonIgnoreFile, Rule: rule.String()}, true
+	}
+
+	return Decision{Path: relDir + "/", Included: true, Reason: ReasonIncluded}, false
+}
+
+// decideFile áp dụng lần lượt: extension → file ignore → điều kiện exclude trong config.
+func (fp *FileProcessor) decideFile(relPath string) Decision {
+	ext := strings.ToLower(path.Ext(relPath))
+
+	// ✅ Kiểm tra extension được hỗ trợ
+	if !fp.config.SupportedExtensions[ext] {
+		rule := ext
+		if rule == "" {
+			rule = "(no extension)"
+		}
+		return Decision{Path: relPath, Reason: ReasonExtension, Rule: rule + " not in supported_extensions"}
+	}
+
+	// ✅ Kiểm tra .gitignore / .copyrightignore
+	if rule := fp.ignore.match(relPath, false); rule != nil {
+		return Decision{Path: relPath, Reason: ReasonIgnoreFile, Rule: rule.String()}
+	}
+
+	// ✅ Kiểm tra file có bị exclude không (theo đường dẫn tương đối)
+	match := fp.config.MatchExclusion(relPath)
+	if match.Excluded {
+		return Decision{Path: relPath, Reason: match.Kind, Rule: match.Rule}
+	}
+
+	decision := Decision{Path: relPath, Included: true, Reason: ReasonIncluded}
+	if match.Kind == config.MatchRule {
+		decision.Rule = match.Rule // Được đưa trở lại bởi rule "!"
+	}
+	return decision
+}
+
+// decideContent kiểm tra nội dung file: marker sinh tự động ở các dòng đầu
+// (// <auto-generated>, Code generated ... DO NOT EDIT), rồi tới dòng dài / file minified.
+func (fp *FileProcessor) decideContent(relPath, ext string, lines []string) Decision {
+	marker, markerLine, generated := fp.config.MatchGeneratedMarker(ext, lines)
+	if !generated {
+		if decision, exclude := fp.decideLongLines(relPath, lines); exclude {
+			return decision
+		}
+		return Decision{Path: relPath, Included: true, Reason: ReasonIncluded}
+	}
+	return Decision{
+		Path:     relPath,
+		Included: fp.config.GeneratedCode.Action == config.GeneratedTag,
+		Reason:   ReasonGeneratedMarker,
+		Rule:     fmt.Sprintf("%q at line %d", marker, markerLine),
+	}
+}
+
+// Explain trả về quyết định cho một file mà không cần scan cả project.
+// Thư mục cha bị bỏ qua cũng được báo cáo (kèm rule của thư mục đó).
+func (fp *FileProcessor) Explain(rootDir, relPath string) (Decision, error) {
+	relPath = strings.TrimPrefix(path.Clean(strings.ReplaceAll(relPath, "\\", "/")), "./")
+
+	fp.rootDir = rootDir
+	fp.ignore = newIgnoreMatcher(rootDir, fp.config)
+
+	readerFor := func(relDir string) (func(string) ([]byte, error), error) {
+		return fp.ignore.diskReader(relDir), nil
+	}
+	if fp.config.GitRef != "" {
+		repo, err := gitrepo.Open(rootDir)
+		if err != nil {
+			return Decision{}, err
+		}
+		defer repo.Close()
+		if readerFor, err = gitDirReaders(repo, rootDir, fp.config.GitRef); err != nil {
+			return Decision{}, err
+		}
+	}
+
+	var readFile func(string) ([]byte, error)
+	for _, dir := range ancestorDirs(relPath) {
+		if dir != "" {
+			if decision, skip := fp.decideDirectory(dir, path.Base(dir)); skip {
+				decision.Rule = fmt.Sprintf("parent directory %s: %s", decision.Path, decision.Rule)
+				decision.Path = relPath
+				return decision, nil
+			}
+		}
+
+		var err error
+		if readFile, err = readerFor(dir); err != nil {
+			return Decision{}, err
+		}
+		if err := fp.ignore.loadDir(dir, readFile); err != nil {
+			return Decision{}, err
+		}
+	}
+
+	decision := fp.decideFile(relPath)
+	if !decision.Included {
+		return decision, nil
+	}
+
+	// ✅ File qua được các rule theo tên: kiểm tra tiếp nội dung (marker sinh tự động)
+	data, err := readFile(path.Base(relPath))
+	if err != nil {
+		return Decision{}, err
+	}
+	if data == nil {
+		return Decision{}, fmt.Errorf("%s: file not found", relPath)
+	}
+	text, encoding, err := fp.decodeContent(relPath, data)
+	if err != nil {
+		return Decision{Path: relPath, Reason: decodeReason(encoding), Rule: err.Error()}, nil
+	}
+	if text, _, err = fp.normalizeText(relPath, text); err != nil {
+		return Decision{Path: relPath, Reason: ReasonBinary, Rule: err.Error()}, nil
+	}
+	ext := strings.ToLower(path.Ext(relPath))
+	if content := fp.decideContent(relPath, ext, splitLines(text)); content.Reason != ReasonIncluded {
+		return content, nil
+	}
+	return decision, nil
+}
+
+// gitDirReaders trả về hàm đọc file ignore của từng thư mục tại git ref.
+func gitDirReaders(repo *gitrepo.Repository, rootDir, ref string) (func(string) (func(string) ([]byte, error), error), error) {
+	commit, err := repo.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	rootTree, err := repo.CommitTree(commit)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := repoRelativeDir(repo.WorkTree(), rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(relDir string) (func(string) ([]byte, error), error) {
+		tree, err := repo.SubTree(rootTree, path.Join(prefix, relDir))
+		if err != nil {
+			return nil, err
+		}
+		entries, err := repo.ReadTree(tree)
+		if err != nil {
+			return nil, err
+		}
+		return func(name string) ([]byte, error) {
+			for _, entry := range entries {
+				if entry.Name == name && entry.IsRegularFile() {
+					return repo.ReadBlob(entry.Hash)
+				}
+			}
+			return nil, nil
+		}, nil
+	}, nil
+}
+
+// ✅ In bảng giải thích; file loại vì extension chỉ được liệt kê khi --verbose
+func (fp *FileProcessor) PrintDecisionTable() {
+	fmt.Fprintf(fp.config.Out(), "📋 File decisions:\n")
+	fmt.Fprintf(fp.config.Out(), "   %-8s %-17s %-40s %s\n", "STATUS", "REASON", "RULE", "PATH")
+
+	hiddenByExtension := 0
+	for _, d := range fp.decisions {
+		if d.Reason == ReasonExtension && fp.config.Verbosity < config.VerbosityVerbose {
+			hiddenByExtension++
+			continue
+		}
+		if d.Included && strings.HasSuffix(d.Path, "/") {
+			continue
+		}
+
+		status := "✅ keep"
+		if !d.Included {
+			status = "🚫 drop"
+		}
+		fmt.Fprintf(fp.config.Out(), "   %-8s %-17s %-40s %s\n", status, d.Reason, d.Rule, d.Path)
+	}
+
+	if hiddenByExtension > 0 {
+		fmt.Fprintf(fp.config.Out(), "   (%d files with unsupported extensions hidden, use --verbose to list them)\n", hiddenByExtension)
+	}
+}
+
+// WriteDecisionsJSON ghi toàn bộ quyết định dạng JSON (dùng cho pipeline).
+func (fp *FileProcessor) WriteDecisionsJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		RootDir    string                `json:"root_dir"`
+		GitRef     string                `json:"git_ref,omitempty"`
+		Commit     string                `json:"commit,omitempty"`
+		Decisions  []Decision            `json:"decisions"`
+		LongLines  []LongLineReport      `json:"long_lines,omitempty"`
+		Normalized []NormalizationReport `json:"normalized,omitempty"`
+		Excerpt    *models.ExcerptReport `json:"excerpt,omitempty"`
+	}{
+		RootDir:    fp.source.RootDir,
+		GitRef:     fp.source.GitRef,
+		Commit:     fp.source.Commit,
+		Decisions:  fp.decisions,
+		LongLines:  fp.longLineFiles,
+		Normalized: fp.normalizedFiles,
+		Excerpt:    fp.excerpt,
+	})
+}
+
+// SetExcerptReport thêm các đoạn đã chọn cho file rút gọn vào báo cáo JSON.
+func (fp *FileProcessor) SetExcerptReport(report *models.ExcerptReport) {
+	fp.excerpt = report
+}
+
+// SaveDecisionsJSON ghi báo cáo JSON ra file.
+func (fp *FileProcessor) SaveDecisionsJSON(filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %v", err)
+	}
+	defer file.Close()
+
+	if err := fp.WriteDecisionsJSON(file); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	return nil
+}
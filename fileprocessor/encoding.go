@@ -0,0 +1,130 @@
+// encoding.go - Detect file encoding (BOM + heuristics) and decode to UTF-8
+package fileprocessor
+
+import (
+	"bytes"
+	"copyright-code-word/config"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// ✅ Tên encoding ghi vào models.CodeFile.Encoding
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+)
+
+// ✅ detectEncoding trả về encodingBinary cho file nhị phân (không decode, báo lý do binary)
+const encodingBinary = "binary"
+
+// Số byte đầu file dùng để đoán UTF-16 không có BOM và file nhị phân
+const utf16SniffBytes = 4096
+
+// decodeContent chuyển nội dung file sang UTF-8, trả về tên encoding đã dùng.
+// Lỗi nếu nội dung không decode được (file nhị phân, encoding sai) thay vì ghi ký tự rác.
+func (fp *FileProcessor) decodeContent(relPath string, data []byte) (string, string, error) {
+	name := fp.config.EncodingFor(relPath)
+	if name == config.EncodingAuto {
+		name = detectEncoding(data, fp.config.Encoding.Fallback)
+	}
+
+	var text string
+	switch name {
+	case encodingBinary:
+		return "", name, fmt.Errorf("NUL bytes, not UTF-8 / UTF-16 text")
+	case EncodingUTF8, EncodingUTF8BOM:
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		if !utf8.Valid(data) {
+			return "", name, fmt.Errorf("not valid UTF-8")
+		}
+		text = string(data)
+	case EncodingUTF16LE, EncodingUTF16BE:
+		endianness := unicode.LittleEndian
+		if name == EncodingUTF16BE {
+			endianness = unicode.BigEndian
+		}
+		decoded, err := unicode.UTF16(endianness, unicode.UseBOM).NewDecoder().Bytes(data)
+		if err != nil {
+			return "", name, fmt.Errorf("cannot decode %s: %v", name, err)
+		}
+		text = string(decoded)
+	default:
+		encoding, err := htmlindex.Get(name)
+		if err != nil {
+			return "", name, fmt.Errorf("unknown encoding %q", name)
+		}
+		decoded, err := encoding.NewDecoder().Bytes(data)
+		if err != nil {
+			return "", name, fmt.Errorf("cannot decode %s: %v", name, err)
+		}
+		text = string(decoded)
+	}
+
+	// ✅ Ký tự thay thế sau khi decode = encoding sai (NUL / file nhị phân: xem normalize.go)
+	if strings.ContainsRune(text, utf8.RuneError) && !bytes.Contains(data, []byte("\xef\xbf\xbd")) {
+		return "", name, fmt.Errorf("contains bytes that are not valid %s", name)
+	}
+
+	return text, name, nil
+}
+
+// detectEncoding đoán encoding: BOM → UTF-16 không BOM (nhiều byte 0) → UTF-8 hợp lệ → nhị phân → fallback.
+func detectEncoding(data []byte, fallback string) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xef\xbb\xbf")):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(data, []byte("\xff\xfe")):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte("\xfe\xff")):
+		return EncodingUTF16BE
+	}
+
+	// Source ASCII dạng UTF-16: một nửa số byte là 0, cùng một phía
+	sample := data
+	if len(sample) > utf16SniffBytes {
+		sample = sample[:utf16SniffBytes]
+	}
+	if len(sample) >= 4 {
+		evenZeros, oddZeros := 0, 0
+		for i, b := range sample {
+			if b != 0 {
+				continue
+			}
+			if i%2 == 0 {
+				evenZeros++
+			} else {
+				oddZeros++
+			}
+		}
+		half := len(sample) / 2
+		switch {
+		case oddZeros > half*3/10 && evenZeros < half/20:
+			return EncodingUTF16LE
+		case evenZeros > half*3/10 && oddZeros < half/20:
+			return EncodingUTF16BE
+		}
+	}
+
+	if utf8.Valid(data) {
+		return EncodingUTF8
+	}
+	// Không phải UTF-8 / UTF-16 mà có byte NUL (giống git): file nhị phân, không thử fallback
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return encodingBinary
+	}
+	return fallback
+}
+
+// decodeReason trả về lý do loại file khi decodeContent lỗi.
+func decodeReason(encoding string) string {
+	if encoding == encodingBinary {
+		return ReasonBinary
+	}
+	return ReasonEncoding
+}
@@ -0,0 +1,147 @@
+// gitsource.go - Scan files tracked by git at a given ref, without touching the working tree
+package fileprocessor
+
+import (
+	"bytes"
+	"copyright-code-word/config"
+	"copyright-code-word/gitrepo"
+	"copyright-code-word/models"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Scan đọc từ git ref nếu Config.GitRef được đặt, ngược lại đọc thư mục trên ổ đĩa.
+func (fp *FileProcessor) Scan(rootDir string) ([]models.CodeFile, error) {
+	if fp.config.GitRef != "" {
+		return fp.ScanGitRef(rootDir, fp.config.GitRef)
+	}
+	return fp.ScanDirectory(rootDir)
+}
+
+// ScanGitRef đọc nội dung file trực tiếp từ .git tại ref (tag, branch, hash).
+// rootDir có thể là thư mục con của repository: chỉ file bên dưới nó được scan.
+func (fp *FileProcessor) ScanGitRef(rootDir, ref string) ([]models.CodeFile, error) {
+	repo, err := gitrepo.Open(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Close()
+
+	commit, err := repo.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := repoRelativeDir(repo.WorkTree(), rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rootTree, err := repo.CommitTree(commit)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := repo.SubTree(rootTree, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%s at %s: %v", rootDir, ref, err)
+	}
+
+	fp.config.Logf(config.VerbosityNormal, "🔍 Scanning for %s files in: %s @ %s (%s)\n",
+		fp.config.Languages.Describe(fp.config.SupportedExtensions), rootDir, ref, commit[:12])
+
+	// ✅ In danh sách exclude để user biết (chỉ khi --verbose)
+	if fp.config.Verbosity >= config.VerbosityVerbose {
+		fmt.Fprintf(fp.config.Out(), "🚫 File exclusion is enabled:\n")
+		fp.config.PrintExcludeList()
+		fmt.Fprintln(fp.config.Out(), strings.Repeat("-", 50))
+	}
+
+	fp.rootDir = rootDir
+	fp.ignore = newIgnoreMatcher(rootDir, fp.config)
+	fp.source = models.SourceInfo{RootDir: rootDir, GitRef: ref, Commit: commit}
+
+	if err := fp.walkTree(repo, tree, ""); err != nil {
+		return nil, err
+	}
+
+	return fp.finishScan(), nil
+}
+
+// walkTree đi qua tree giống filepath.WalkDir: thư mục trước, rồi tới các entry bên trong.
+func (fp *FileProcessor) walkTree(repo *gitrepo.Repository, treeHash, relDir string) error {
+	entries, err := repo.ReadTree(treeHash)
+	if err != nil {
+		return err
+	}
+
+	// ✅ File ignore được đọc từ chính tree tại ref, không phải từ working tree
+	readFile := func(name string) ([]byte, error) {
+		for _, entry := range entries {
+			if entry.Name == name && entry.IsRegularFile() {
+				return repo.ReadBlob(entry.Hash)
+			}
+		}
+		return nil, nil
+	}
+
+	name := path.Base(relDir)
+	if relDir == "" {
+		name = "."
+	}
+	skip, err := fp.handleDirectory(relDir, name, readFile)
+	if skip || err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		relPath := path.Join(relDir, entry.Name)
+
+		if entry.IsDir {
+			if err := fp.walkTree(repo, entry.Hash, relPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if !entry.IsRegularFile() {
+			continue
+		}
+
+		blobHash := entry.Hash
+		if err := fp.handleFile(relPath, func() (io.ReadCloser, error) {
+			data, err := repo.ReadBlob(blobHash)
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repoRelativeDir trả về đường dẫn (dùng "/") của dir so với gốc repository.
+func repoRelativeDir(workTree, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(workTree); err == nil {
+		workTree = resolved
+	}
+
+	rel, err := filepath.Rel(workTree, absDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside repository %s", dir, workTree)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
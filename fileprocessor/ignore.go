@@ -0,0 +1,189 @@
+// ignore.go - .gitignore and .copyrightignore support for ScanDirectory
+package fileprocessor
+
+import (
+	"bufio"
+	"bytes"
+	"copyright-code-word/config"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ✅ File ignore được đọc ở mỗi thư mục (theo thứ tự, file sau ghi đè file trước)
+const (
+	GitIgnoreFile       = ".gitignore"
+	CopyrightIgnoreFile = ".copyrightignore"
+)
+
+// ✅ Một dòng trong file ignore
+type ignoreRule struct {
+	source  string // ".gitignore:12" (đường dẫn tương đối + số dòng)
+	pattern string // Dòng gốc
+	negate  bool
+	dirOnly bool
+	regex   *regexp.Regexp
+}
+
+// String trả về mô tả rule để báo cáo, ví dụ: lib/.gitignore:3 "*.log"
+func (r *ignoreRule) String() string {
+	return fmt.Sprintf("%s %q", r.source, r.pattern)
+}
+
+// ✅ Tập rule theo từng thư mục, áp dụng theo ngữ nghĩa .gitignore
+type ignoreMatcher struct {
+	rootDir    string
+	fileNames  []string
+	rulesByDir map[string][]*ignoreRule
+	out        io.Writer // Cảnh báo rule sai
+}
+
+func newIgnoreMatcher(rootDir string, cfg *config.Config) *ignoreMatcher {
+	var fileNames []string
+	if cfg.RespectGitignore {
+		fileNames = append(fileNames, GitIgnoreFile)
+	}
+	if cfg.UseCopyrightIgnore {
+		fileNames = append(fileNames, CopyrightIgnoreFile)
+	}
+
+	return &ignoreMatcher{
+		rootDir:    rootDir,
+		fileNames:  fileNames,
+		rulesByDir: make(map[string][]*ignoreRule),
+		out:        cfg.Out(),
+	}
+}
+
+// loadDir đọc file ignore trong thư mục relDir (gọi khi đi vào thư mục).
+// readFile trả về nội dung file theo tên, (nil, nil) nếu không tồn tại.
+func (m *ignoreMatcher) loadDir(relDir string, readFile func(name string) ([]byte, error)) error {
+	var rules []*ignoreRule
+	for _, name := range m.fileNames {
+		data, err := readFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path.Join(relDir, name), err)
+		}
+		if data != nil {
+			rules = append(rules, parseIgnoreFile(data, path.Join(relDir, name), m.out)...)
+		}
+	}
+	if len(rules) > 0 {
+		m.rulesByDir[relDir] = rules
+	}
+	return nil
+}
+
+// diskReader đọc file ignore từ thư mục trên ổ đĩa.
+func (m *ignoreMatcher) diskReader(relDir string) func(name string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(m.rootDir, filepath.FromSlash(relDir), name))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	}
+}
+
+// match trả về rule khớp cuối cùng nếu đường dẫn bị ignore, nil nếu không.
+func (m *ignoreMatcher) match(relPath string, isDir bool) *ignoreRule {
+	var matched *ignoreRule
+
+	for _, dir := range ancestorDirs(relPath) {
+		for _, rule := range m.rulesByDir[dir] {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			target := relPath
+			if dir != "" {
+				target = strings.TrimPrefix(relPath, dir+"/")
+			}
+			if rule.regex.MatchString(target) {
+				matched = rule
+			}
+		}
+	}
+
+	if matched == nil || matched.negate {
+		return nil
+	}
+	return matched
+}
+
+// ancestorDirs trả về các thư mục cha từ root xuống: "a/b/c.cs" → "", "a", "a/b"
+func ancestorDirs(relPath string) []string {
+	dirs := []string{""}
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		dirs = append(dirs, strings.Join(parts[:i], "/"))
+	}
+	return dirs
+}
+
+func parseIgnoreFile(data []byte, source string, out io.Writer) []*ignoreRule {
+	var rules []*ignoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		rule, err := parseIgnoreLine(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(out, "⚠️  %s:%d: %v (rule skipped)\n", source, lineNum, err)
+			continue
+		}
+		if rule == nil {
+			continue
+		}
+		rule.source = fmt.Sprintf("%s:%d", source, lineNum)
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// parseIgnoreLine parse một dòng theo cú pháp .gitignore; trả về nil cho dòng trống/comment.
+func parseIgnoreLine(line string) (*ignoreRule, error) {
+	line = strings.TrimSuffix(line, "\r")
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	// Khoảng trắng cuối dòng bị bỏ trừ khi được escape
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	rule := &ignoreRule{pattern: line}
+	pattern := line
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, "\\!") || strings.HasPrefix(pattern, "\\#") {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if pattern == "" {
+		return nil, nil
+	}
+
+	// Có "/" ở đầu hoặc giữa → neo vào thư mục chứa file ignore
+	if strings.Contains(pattern, "/") && !strings.HasPrefix(pattern, "/") {
+		pattern = "/" + pattern
+	}
+
+	regex, err := config.CompileGlob(pattern, false)
+	if err != nil {
+		return nil, err
+	}
+	rule.regex = regex
+	return rule, nil
+}
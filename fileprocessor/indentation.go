@@ -0,0 +1,49 @@
+// indentation.go - Expand tabs and trim trailing whitespace before pagination
+package fileprocessor
+
+import (
+	"copyright-code-word/config"
+	"strings"
+)
+
+// normalizeIndentation áp dụng cấu hình indentation cho từng dòng (sửa trực tiếp slice).
+// Trả về số dòng đã thay đổi.
+func (fp *FileProcessor) normalizeIndentation(lines []string) int {
+	settings := fp.config.Indentation
+	changed := 0
+	for i, line := range lines {
+		normalized := line
+		if settings.TrimTrailingWhitespace {
+			normalized = strings.TrimRight(normalized, " \t")
+		}
+		if settings.Tabs == config.TabsExpand {
+			normalized = expandTabs(normalized, settings.TabWidth)
+		}
+		if normalized != line {
+			lines[i] = normalized
+			changed++
+		}
+	}
+	return changed
+}
+
+// expandTabs đổi tab thành khoảng trắng tới tab stop kế tiếp (theo cột, không theo byte).
+func expandTabs(line string, tabWidth int) string {
+	if !strings.ContainsRune(line, '\t') {
+		return line
+	}
+
+	var result strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := tabWidth - column%tabWidth
+			result.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		result.WriteRune(r)
+		column++
+	}
+	return result.String()
+}
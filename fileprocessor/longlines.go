@@ -0,0 +1,109 @@
+// longlines.go - Detect very long lines and minified / one-line files, apply long_lines policy
+package fileprocessor
+
+import (
+	"copyright-code-word/config"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LongLineReport mô tả một file có dòng dài hơn long_lines.max_length.
+type LongLineReport struct {
+	Path        string `json:"path"`
+	LongLines   int    `json:"long_lines"`   // Số dòng vượt max_length
+	Longest     int    `json:"longest"`      // Dòng dài nhất (ký tự)
+	LongestLine int    `json:"longest_line"` // Vị trí dòng dài nhất, bắt đầu từ 1
+	Minified    bool   `json:"minified"`
+	Action      string `json:"action"`
+}
+
+// LongLineFiles trả về các file có dòng dài của lần scan gần nhất.
+func (fp *FileProcessor) LongLineFiles() []LongLineReport {
+	return fp.longLineFiles
+}
+
+// checkLongLines trả về ok = false nếu file không có dòng nào vượt max_length.
+// File minified: tên có ".min.", chỉ có một dòng không rỗng, hoặc ít nhất một nửa số dòng không rỗng là dòng dài.
+func (fp *FileProcessor) checkLongLines(relPath string, lines []string) (LongLineReport, bool) {
+	settings := fp.config.LongLines
+	report := LongLineReport{Path: relPath}
+
+	nonEmpty := 0
+	for i, line := range lines {
+		length := utf8.RuneCountInString(line)
+		if length > settings.MaxLength {
+			report.LongLines++
+		}
+		if length > report.Longest {
+			report.Longest, report.LongestLine = length, i+1
+		}
+		if strings.TrimSpace(line) != "" {
+			nonEmpty++
+		}
+	}
+	if report.LongLines == 0 {
+		return report, false
+	}
+
+	report.Minified = strings.Contains(strings.ToLower(path.Base(relPath)), ".min.") ||
+		nonEmpty == 1 || report.LongLines*2 >= nonEmpty
+	report.Action = fp.config.LongLineActionFor(relPath, report.Minified)
+	return report, true
+}
+
+// describe trả về mô tả ngắn dùng cho log và cột RULE của bảng quyết định.
+func (r LongLineReport) describe(maxLength int) string {
+	kind := "long lines"
+	if r.Minified {
+		kind = "minified"
+	}
+	return fmt.Sprintf("%s: %d lines > %d chars, longest %d at line %d",
+		kind, r.LongLines, maxLength, r.Longest, r.LongestLine)
+}
+
+// decideLongLines trả về quyết định loại file nếu action = exclude.
+func (fp *FileProcessor) decideLongLines(relPath string, lines []string) (Decision, bool) {
+	report, ok := fp.checkLongLines(relPath, lines)
+	if !ok || report.Action != config.LongLineExclude {
+		return Decision{}, false
+	}
+
+	reason := ReasonLongLines
+	if report.Minified {
+		reason = ReasonMinified
+	}
+	return Decision{Path: relPath, Reason: reason, Rule: report.describe(fp.config.LongLines.MaxLength)}, true
+}
+
+// truncateLines cắt các dòng vượt max_length (theo ký tự) và thêm truncate_marker.
+func (fp *FileProcessor) truncateLines(lines []string) []string {
+	maxLength := fp.config.LongLines.MaxLength
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		length := utf8.RuneCountInString(line)
+		if length <= maxLength {
+			result[i] = line
+			continue
+		}
+		runes := []rune(line)
+		marker := strings.ReplaceAll(fp.config.LongLines.TruncateMarker, "{chars}", strconv.Itoa(length-maxLength))
+		result[i] = string(runes[:maxLength]) + marker
+	}
+	return result
+}
+
+// splitLines tách nội dung thành các dòng, không giới hạn độ dài dòng
+// (thay cho bufio.Scanner vốn lỗi "token too long" với dòng > 64 KiB).
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,162 @@
+// normalize.go - Unify line endings, escape or strip control characters, detect binary files
+package fileprocessor
+
+import (
+	"copyright-code-word/config"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ✅ Kiểu xuống dòng của file gốc
+const (
+	LineEndingLF    = "lf"
+	LineEndingCRLF  = "crlf"
+	LineEndingCR    = "cr"
+	LineEndingMixed = "mixed"
+)
+
+// NormalizationReport ghi lại những gì đã thay đổi trong nội dung file.
+type NormalizationReport struct {
+	Path         string         `json:"path"`
+	LineEndings  string         `json:"line_endings"`                 // lf, crlf, cr, mixed ("" = không có xuống dòng)
+	LineCounts   map[string]int `json:"line_ending_counts,omitempty"` // Chỉ ghi khi mixed
+	ControlChars map[string]int `json:"control_chars,omitempty"`      // "U+000C" → số lần
+	Action       string         `json:"action,omitempty"`             // escape / strip
+}
+
+// NormalizedFiles trả về các file đã được chuẩn hoá của lần scan gần nhất.
+func (fp *FileProcessor) NormalizedFiles() []NormalizationReport {
+	return fp.normalizedFiles
+}
+
+// changed cho biết nội dung có khác ngoài việc CRLF → LF (CRLF thuần không cần báo cáo).
+func (r NormalizationReport) changed() bool {
+	return r.LineEndings == LineEndingMixed || r.LineEndings == LineEndingCR || len(r.ControlChars) > 0
+}
+
+// describe trả về mô tả ngắn: "mixed line endings (crlf 10, lf 2), 3 control chars U+000C escaped".
+func (r NormalizationReport) describe() string {
+	var parts []string
+	switch r.LineEndings {
+	case LineEndingMixed:
+		var counts []string
+		for _, kind := range []string{LineEndingCRLF, LineEndingLF, LineEndingCR} {
+			if r.LineCounts[kind] > 0 {
+				counts = append(counts, fmt.Sprintf("%s %d", kind, r.LineCounts[kind]))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("mixed line endings (%s)", strings.Join(counts, ", ")))
+	case LineEndingCR:
+		parts = append(parts, "CR line endings")
+	}
+	if len(r.ControlChars) > 0 {
+		codes := make([]string, 0, len(r.ControlChars))
+		total := 0
+		for code, count := range r.ControlChars {
+			codes = append(codes, code)
+			total += count
+		}
+		sort.Strings(codes)
+		action := "escaped"
+		if r.Action == config.ControlCharsStrip {
+			action = "stripped"
+		}
+		parts = append(parts, fmt.Sprintf("%d control chars %s %s", total, strings.Join(codes, " "), action))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isControlChar trả về true với ký tự không hợp lệ hoặc không hiển thị được trong OOXML:
+// C0 (trừ tab, LF, CR), DEL, C1, U+FFFE / U+FFFF.
+func isControlChar(r rune) bool {
+	switch {
+	case r == '\t', r == '\n', r == '\r':
+		return false
+	case r < 0x20, r == 0x7F, r >= 0x80 && r <= 0x9F, r == 0xFFFE, r == 0xFFFF:
+		return true
+	}
+	return false
+}
+
+// ✅ File có từ binaryNULCount NUL trở lên và NUL chiếm hơn binaryNULShare nội dung là file nhị phân dù chưa vượt
+// binary_threshold; file text chỉ có vài NUL lạc (ví dụ "\0" trong chuỗi) thì NUL được escape / bỏ như ký tự điều khiển khác.
+const (
+	binaryNULCount = 16
+	binaryNULShare = 0.01
+)
+
+// normalizeText chuẩn hoá nội dung đã decode: CRLF / CR → LF, ký tự điều khiển (kể cả NUL) → escape hoặc bỏ.
+// Lỗi nếu file trông như file nhị phân (quá nhiều ký tự điều khiển hoặc quá nhiều NUL).
+func (fp *FileProcessor) normalizeText(relPath, text string) (string, NormalizationReport, error) {
+	settings := fp.config.Normalization
+	report := NormalizationReport{Path: relPath, LineCounts: make(map[string]int)}
+
+	// ✅ Nhận diện file nhị phân trước khi sửa nội dung
+	controls, nuls, total := 0, 0, 0
+	for _, r := range text {
+		total++
+		if r == 0 {
+			nuls++
+		}
+		if isControlChar(r) {
+			controls++
+		}
+	}
+	if nuls >= binaryNULCount && float64(nuls)/float64(total) > binaryNULShare {
+		return "", report, fmt.Errorf("%d of %d characters are NUL", nuls, total)
+	}
+	if total > 0 && float64(controls)/float64(total) > settings.BinaryThreshold {
+		return "", report, fmt.Errorf("%d of %d characters are control characters (normalization.binary_threshold = %g)",
+			controls, total, settings.BinaryThreshold)
+	}
+
+	var result strings.Builder
+	result.Grow(len(text))
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\r' && i+1 < len(runes) && runes[i+1] == '\n':
+			report.LineCounts[LineEndingCRLF]++
+			result.WriteByte('\n')
+			i++
+		case r == '\r':
+			report.LineCounts[LineEndingCR]++
+			result.WriteByte('\n')
+		case r == '\n':
+			report.LineCounts[LineEndingLF]++
+			result.WriteByte('\n')
+		case isControlChar(r):
+			code := fmt.Sprintf("U+%04X", r)
+			if report.ControlChars == nil {
+				report.ControlChars = make(map[string]int)
+			}
+			report.ControlChars[code]++
+			if settings.ControlChars == config.ControlCharsEscape {
+				if r < 0x100 {
+					fmt.Fprintf(&result, "\\x%02X", r)
+				} else {
+					fmt.Fprintf(&result, "\\u%04X", r)
+				}
+			}
+		default:
+			result.WriteRune(r)
+		}
+	}
+
+	switch len(report.LineCounts) {
+	case 0:
+	case 1:
+		for kind := range report.LineCounts {
+			report.LineEndings = kind
+		}
+		report.LineCounts = nil
+	default:
+		report.LineEndings = LineEndingMixed
+	}
+	if len(report.ControlChars) > 0 {
+		report.Action = settings.ControlChars
+	}
+	return result.String(), report, nil
+}
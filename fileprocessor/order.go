@@ -0,0 +1,58 @@
+// order.go - Order of files in the generated documents (ordering.strategy)
+package fileprocessor
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"sort"
+	"strings"
+)
+
+// sortFiles sắp xếp file theo ordering.strategy; hai file "bằng nhau" luôn được xếp theo đường dẫn
+// để thứ tự ổn định giữa các lần chạy.
+func (fp *FileProcessor) sortFiles(files []models.CodeFile) {
+	var key func(file models.CodeFile) int
+	switch fp.config.Ordering.Strategy {
+	case config.OrderDepth:
+		key = func(file models.CodeFile) int { return strings.Count(file.RelPath, "/") }
+	case config.OrderPriority:
+		key = func(file models.CodeFile) int { return fp.config.PriorityRank(file.RelPath) }
+	case config.OrderEntryPoints:
+		key = func(file models.CodeFile) int { return fp.config.EntryPointRank(file.RelPath) }
+	case config.OrderSize:
+		key = func(file models.CodeFile) int { return -len(file.Lines) }
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if fp.config.Ordering.Strategy == config.OrderLanguage && a.Language != b.Language {
+			return strings.ToLower(a.Language) < strings.ToLower(b.Language)
+		}
+		if key != nil {
+			if keyA, keyB := key(a), key(b); keyA != keyB {
+				return keyA < keyB
+			}
+		}
+		return lessPath(a.RelPath, b.RelPath)
+	})
+}
+
+// lessPath so sánh theo cấu trúc thư mục: trong cùng thư mục, file đứng trước thư mục con,
+// tên được so sánh không phân biệt hoa thường.
+func lessPath(a, b string) bool {
+	aParts, bParts := strings.Split(a, "/"), strings.Split(b, "/")
+	for k := 0; k < len(aParts) && k < len(bParts); k++ {
+		aIsFile, bIsFile := k == len(aParts)-1, k == len(bParts)-1
+		if aIsFile != bIsFile {
+			return aIsFile
+		}
+		if aParts[k] == bParts[k] {
+			continue
+		}
+		if aLower, bLower := strings.ToLower(aParts[k]), strings.ToLower(bParts[k]); aLower != bLower {
+			return aLower < bLower
+		}
+		return aParts[k] < bParts[k]
+	}
+	return len(aParts) < len(bParts)
+}
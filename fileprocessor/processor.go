@@ -2,163 +2,382 @@
 package fileprocessor
 
 import (
-	"bufio"
 	"copyright-code-word/config"
 	"copyright-code-word/models"
+	"copyright-code-word/paginator"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 )
 
 type FileProcessor struct {
-	config        *config.Config
-	files         []models.CodeFile
-	excludedCount int // ✅ Đếm số file bị exclude
+	config    *config.Config
+	rootDir   string
+	ignore    *ignoreMatcher
+	files     []models.CodeFile
+	decisions []Decision // ✅ Lý do giữ / loại của từng file (xem decisions.go)
+	// ✅ File có dòng dài / minified (xem longlines.go)
+	longLineFiles []LongLineReport
+	// ✅ File đã chuẩn hoá xuống dòng / ký tự điều khiển (xem normalize.go)
+	normalizedFiles []NormalizationReport
+	source          models.SourceInfo
+	// ✅ Nội dung file rút gọn do generator chọn (SetExcerptReport)
+	excerpt *models.ExcerptReport
+}
+
+// ✅ Thư mục luôn bị bỏ qua (build output, dependency, metadata)
+var skipDirs = map[string]bool{
+	"node_modules": true, ".git": true, "vendor": true,
+	"target": true, "__pycache__": true, ".next": true,
+	"build": true, "dist": true, "bin": true, "obj": true,
+	".dart_tool": true, ".packages": true,
 }
 
 func New(cfg *config.Config) *FileProcessor {
 	return &FileProcessor{
-		config:        cfg,
-		files:         make([]models.CodeFile, 0),
-		excludedCount: 0,
+		config: cfg,
+		files:  make([]models.CodeFile, 0),
 	}
 }
 
 func (fp *FileProcessor) ScanDirectory(rootDir string) ([]models.CodeFile, error) {
-	fmt.Printf("🔍 Scanning for .cs and .dart files in: %s\n", rootDir)
+	fp.config.Logf(config.VerbosityNormal, "🔍 Scanning for %s files in: %s\n",
+		fp.config.Languages.Describe(fp.config.SupportedExtensions), rootDir)
 
-	// ✅ In danh sách exclude để user biết
-	fmt.Printf("🚫 File exclusion is enabled:\n")
-	fp.config.PrintExcludeList()
-	fmt.Println(strings.Repeat("-", 50))
+	// ✅ In danh sách exclude để user biết (chỉ khi --verbose)
+	if fp.config.Verbosity >= config.VerbosityVerbose {
+		fmt.Fprintf(fp.config.Out(), "🚫 File exclusion is enabled:\n")
+		fp.config.PrintExcludeList()
+		fmt.Fprintln(fp.config.Out(), strings.Repeat("-", 50))
+	}
 
+	fp.rootDir = rootDir
+	fp.ignore = newIgnoreMatcher(rootDir, fp.config)
+	fp.source = models.SourceInfo{RootDir: rootDir}
 	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
+		relPath := fp.relativePath(path)
 		if d.IsDir() {
-			return fp.handleDirectory(d)
+			skip, err := fp.handleDirectory(relPath, d.Name(), fp.ignore.diskReader(relPath))
+			if skip {
+				return filepath.SkipDir
+			}
+			return err
 		}
 
-		return fp.handleFile(path)
+		return fp.handleFile(relPath, func() (io.ReadCloser, error) {
+			return os.Open(path)
+		})
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Sort files by name
-	sort.Slice(fp.files, func(i, j int) bool {
-		return fp.files[i].FileName < fp.files[j].FileName
-	})
+	return fp.finishScan(), nil
+}
+
+// Source trả về thông tin nguồn của lần scan gần nhất (thư mục, git ref, commit).
+func (fp *FileProcessor) Source() models.SourceInfo {
+	return fp.source
+}
+
+// ✅ Sắp xếp + in thống kê, dùng chung cho scan thư mục và scan git ref
+func (fp *FileProcessor) finishScan() []models.CodeFile {
+	// ✅ Sắp xếp theo ordering.strategy (mặc định: cấu trúc thư mục)
+	fp.sortFiles(fp.files)
 
 	// ✅ In thống kê
 	fp.printScanSummary()
 
-	return fp.files, nil
+	return fp.files
 }
 
-func (fp *FileProcessor) handleDirectory(d fs.DirEntry) error {
-	skipDirs := map[string]bool{
-		"node_modules": true, ".git": true, "vendor": true,
-		"target": true, "__pycache__": true, ".next": true,
-		"build": true, "dist": true, "bin": true, "obj": true,
-		".dart_tool": true, ".packages": true,
+// handleDirectory trả về skip = true nếu cần bỏ qua toàn bộ thư mục.
+// relDir là đường dẫn tương đối ("." = thư mục gốc), readFile đọc file trong thư mục đó.
+func (fp *FileProcessor) handleDirectory(relDir, name string, readFile func(string) ([]byte, error)) (bool, error) {
+	if relDir == "." {
+		relDir = ""
 	}
 
-	if skipDirs[d.Name()] {
-		return filepath.SkipDir
+	if relDir != "" {
+		if decision, skip := fp.decideDirectory(relDir, name); skip {
+			fp.record(decision)
+			if decision.Reason == ReasonIgnoreFile {
+				fp.config.Logf(config.VerbosityVerbose, "🙈 Ignored directory: %s (%s)\n", decision.Path, decision.Rule)
+			}
+			return true, nil
+		}
 	}
-	return nil
+
+	// ✅ Đọc .gitignore / .copyrightignore của thư mục này
+	return false, fp.ignore.loadDir(relDir, readFile)
 }
 
-func (fp *FileProcessor) handleFile(path string) error {
-	ext := strings.ToLower(filepath.Ext(path))
-	filename := filepath.Base(path)
+// handleFile xử lý một file theo đường dẫn tương đối; open mở nội dung file (ổ đĩa hoặc git).
+func (fp *FileProcessor) handleFile(relPath string, open func() (io.ReadCloser, error)) error {
+	ext := strings.ToLower(path.Ext(relPath))
 
-	// ✅ Kiểm tra extension được hỗ trợ
-	if !fp.config.SupportedExtensions[ext] {
+	decision := fp.decideFile(relPath)
+	switch {
+	case decision.Reason == ReasonExtension:
+		fp.record(decision)
+		return nil
+	case decision.Reason == ReasonIgnoreFile:
+		fp.config.Logf(config.VerbosityNormal, "🙈 Ignored: %s (%s)\n", relPath, decision.Rule)
+		fp.record(decision)
+		return nil
+	case decision.Reason == ReasonGeneratedSuffix:
+		fp.config.Logf(config.VerbosityNormal, "🤖 Generated: %s (%s)\n", relPath, decision.Rule)
+		fp.record(decision)
+		return nil
+	case !decision.Included:
+		fp.config.Logf(config.VerbosityNormal, "🚫 Excluded: %s (%s: %s)\n", relPath, decision.Reason, decision.Rule)
+		fp.record(decision)
 		return nil
 	}
 
-	// ✅ Kiểm tra file có bị exclude không
-	if fp.config.IsFileExcluded(filename) {
-		fmt.Printf("🚫 Excluded: %s (sensitive file)\n", filename)
-		fp.excludedCount++
+	reader, err := open()
+	if err != nil {
+		fmt.Fprintf(fp.config.Out(), "❌ Error processing %s: failed to open file: %v\n", relPath, err)
+		fp.record(Decision{Path: relPath, Reason: ReasonError, Rule: err.Error()})
 		return nil
 	}
+	defer reader.Close()
 
-	if err := fp.processFile(path, ext); err != nil {
-		fmt.Printf("❌ Error processing %s: %v\n", path, err)
-	} else {
-		fmt.Printf("📄 Added: %s\n", filename)
+	result, err := fp.processFile(relPath, ext, reader)
+	switch {
+	case err != nil:
+		fmt.Fprintf(fp.config.Out(), "❌ Error processing %s: %v\n", relPath, err)
+		fp.record(Decision{Path: relPath, Reason: ReasonError, Rule: err.Error()})
+	case result.Reason == ReasonEncoding:
+		fmt.Fprintf(fp.config.Out(), "⚠️  Skipped undecodable file: %s (%s)\n", relPath, result.Rule)
+		fp.record(result)
+	case result.Reason == ReasonBinary:
+		fmt.Fprintf(fp.config.Out(), "⚠️  Skipped binary file: %s (%s)\n", relPath, result.Rule)
+		fp.record(result)
+	case result.Reason == ReasonMinified, result.Reason == ReasonLongLines:
+		fp.config.Logf(config.VerbosityNormal, "📏 Excluded: %s (%s)\n", relPath, result.Rule)
+		fp.record(result)
+	case result.Reason == ReasonGeneratedMarker && !result.Included:
+		fp.config.Logf(config.VerbosityNormal, "🤖 Generated: %s (%s)\n", relPath, result.Rule)
+		fp.record(result)
+	case result.Included:
+		if result.Reason == ReasonGeneratedMarker {
+			fp.config.Logf(config.VerbosityNormal, "📄 Added: %s (generated, %s)\n", relPath, result.Rule)
+			fp.record(result)
+		} else if result.Rule != "" {
+			fp.config.Logf(config.VerbosityNormal, "📄 Added: %s (%s)\n", relPath, result.Rule)
+			if decision.Rule != "" {
+				result.Rule = decision.Rule + "; " + result.Rule
+			}
+			fp.record(result)
+		} else {
+			fp.config.Logf(config.VerbosityNormal, "📄 Added: %s\n", relPath)
+			fp.record(decision)
+		}
+	default:
+		fp.record(result)
 	}
 
 	return nil
 }
 
-func (fp *FileProcessor) processFile(filePath, ext string) error {
-	file, err := os.Open(filePath)
+// ✅ Đường dẫn tương đối so với thư mục gốc, dùng "/" trên mọi OS
+func (fp *FileProcessor) relativePath(path string) string {
+	rel, err := filepath.Rel(fp.rootDir, path)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %v", err)
+		rel = filepath.Base(path)
 	}
-	defer file.Close()
+	return filepath.ToSlash(rel)
+}
 
-	var lines []string
-	var content strings.Builder
-	scanner := bufio.NewScanner(file)
+// processFile đọc nội dung file và trả về quyết định dựa trên nội dung
+// (file rỗng, marker sinh tự động); file được giữ lại sẽ được thêm vào fp.files.
+func (fp *FileProcessor) processFile(relPath, ext string, file io.Reader) (Decision, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return Decision{}, fmt.Errorf("error reading file: %v", err)
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-		content.WriteString(line + "\n")
+	// ✅ Nhận diện encoding và chuyển sang UTF-8; file không decode được sẽ bị báo cáo
+	text, encoding, err := fp.decodeContent(relPath, data)
+	if err != nil {
+		return Decision{Path: relPath, Reason: decodeReason(encoding), Rule: err.Error()}, nil
+	}
+	if encoding != EncodingUTF8 {
+		fp.config.Logf(config.VerbosityVerbose, "🔤 Decoded %s from %s\n", relPath, encoding)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading file: %v", err)
+	// ✅ Chuẩn hoá xuống dòng + ký tự điều khiển; file nhị phân bị bỏ qua
+	text, normalization, err := fp.normalizeText(relPath, text)
+	if err != nil {
+		return Decision{Path: relPath, Reason: ReasonBinary, Rule: err.Error()}, nil
+	}
+	if normalization.changed() {
+		fp.normalizedFiles = append(fp.normalizedFiles, normalization)
+		fp.config.Logf(config.VerbosityVerbose, "🧹 Normalized %s: %s\n", relPath, normalization.describe())
 	}
 
+	lines := splitLines(text)
 	if len(lines) == 0 {
-		fmt.Printf("⚠️  Skipped empty file: %s\n", filepath.Base(filePath))
-		return nil
+		fmt.Fprintf(fp.config.Out(), "⚠️  Skipped empty file: %s\n", relPath)
+		return Decision{Path: relPath, Reason: ReasonEmpty}, nil
+	}
+
+	// ✅ Tab / khoảng trắng cuối dòng theo indentation (trước khi đếm độ dài dòng)
+	if changed := fp.normalizeIndentation(lines); changed > 0 {
+		fp.config.Logf(config.VerbosityVerbose, "↹  Normalized indentation in %s (%d lines)\n", relPath, changed)
+	}
+
+	result := fp.decideContent(relPath, ext, lines)
+
+	// ✅ Dòng dài: ghi vào báo cáo, cắt dòng nếu action = truncate
+	if report, ok := fp.checkLongLines(relPath, lines); ok && (result.Included || result.Reason != ReasonGeneratedMarker) {
+		fp.longLineFiles = append(fp.longLineFiles, report)
+		if result.Included {
+			if result.Rule != "" {
+				result.Rule += "; "
+			}
+			result.Rule += report.describe(fp.config.LongLines.MaxLength) + " → " + report.Action
+		}
+		if report.Action == config.LongLineTruncate {
+			lines = fp.truncateLines(lines)
+		}
+	}
+
+	if !result.Included {
+		return result, nil
+	}
+
+	var content strings.Builder
+	for _, line := range lines {
+		content.WriteString(line + "\n")
 	}
 
 	// Calculate page count
-	totalLines := len(lines) + fp.config.CompactHeaderLines + fp.config.FileSeparatorLines
-	pageCount := (totalLines + fp.config.LinesPerPage - 1) / fp.config.LinesPerPage
+	totalLines := paginator.FileRows(models.CodeFile{Lines: lines}, fp.config.CodeWrapWidth(), fp.config.Indentation.TabWidth) +
+		fp.config.CompactHeaderLines + fp.config.FileSeparatorLines
+	pageCount := (totalLines + fp.config.RowsPerPage() - 1) / fp.config.RowsPerPage()
 	if pageCount == 0 {
 		pageCount = 1
 	}
 
 	fp.files = append(fp.files, models.CodeFile{
-		FileName:  filepath.Base(filePath),
+		FileName:  path.Base(relPath),
+		RelPath:   relPath,
 		Extension: ext,
+		Language:  fp.config.LanguageFor(ext).Name,
+		Encoding:  encoding,
+		Generated: result.Reason == ReasonGeneratedMarker,
 		Lines:     lines,
 		Content:   content.String(),
 		PageCount: pageCount,
 	})
 
-	return nil
+	return result, nil
 }
 
 // ✅ Hàm in thống kê scan
 func (fp *FileProcessor) printScanSummary() {
-	fmt.Println(strings.Repeat("-", 50))
-	fmt.Printf("📊 Scan Summary:\n")
-	fmt.Printf("   ✅ Files included: %d\n", len(fp.files))
-	fmt.Printf("   🚫 Files excluded: %d\n", fp.excludedCount)
-	fmt.Printf("   📁 Total processed: %d\n", len(fp.files)+fp.excludedCount)
-
-	if len(fp.files) > 0 {
-		fmt.Printf("📋 Included files:\n")
+	fmt.Fprintln(fp.config.Out(), strings.Repeat("-", 50))
+	fmt.Fprintf(fp.config.Out(), "📊 Scan Summary:\n")
+	fmt.Fprintf(fp.config.Out(), "   ✅ Files included: %d\n", len(fp.files))
+	excludedCount, ignoredCount := 0, 0
+	var generatedFiles, undecodableFiles []Decision
+	for _, d := range fp.decisions {
+		switch {
+		case d.Reason == ReasonEncoding, d.Reason == ReasonBinary:
+			undecodableFiles = append(undecodableFiles, d)
+		case d.Reason == ReasonIgnoreFile && !strings.HasSuffix(d.Path, "/"):
+			ignoredCount++
+		case d.Reason == ReasonGeneratedSuffix, d.Reason == ReasonGeneratedMarker:
+			generatedFiles = append(generatedFiles, d)
+		case d.Reason == ReasonExactName, d.Reason == ReasonPattern, d.Reason == ReasonRule && !d.Included:
+			excludedCount++
+		}
+	}
+
+	generatedExcluded := 0
+	for _, d := range generatedFiles {
+		if !d.Included {
+			generatedExcluded++
+		}
+	}
+
+	fmt.Fprintf(fp.config.Out(), "   🚫 Files excluded (sensitive): %d\n", excludedCount)
+	fmt.Fprintf(fp.config.Out(), "   🤖 Files excluded (generated): %d\n", generatedExcluded)
+	if tagged := len(generatedFiles) - generatedExcluded; tagged > 0 {
+		fmt.Fprintf(fp.config.Out(), "   🏷️  Generated files kept (tagged): %d\n", tagged)
+	}
+	fmt.Fprintf(fp.config.Out(), "   🙈 Files ignored (.gitignore/.copyrightignore): %d\n", ignoredCount)
+	if len(undecodableFiles) > 0 {
+		fmt.Fprintf(fp.config.Out(), "   ⚠️  Files undecodable (encoding / binary): %d\n", len(undecodableFiles))
+	}
+	longLinesExcluded := 0
+	for _, report := range fp.longLineFiles {
+		if report.Action == config.LongLineExclude {
+			longLinesExcluded++
+		}
+	}
+	if len(fp.longLineFiles) > 0 {
+		fmt.Fprintf(fp.config.Out(), "   📏 Files with long lines / minified: %d (%d excluded)\n", len(fp.longLineFiles), longLinesExcluded)
+	}
+	fmt.Fprintf(fp.config.Out(), "   📁 Total processed: %d\n",
+		len(fp.files)+excludedCount+generatedExcluded+ignoredCount+len(undecodableFiles)+longLinesExcluded)
+
+	if len(fp.files) > 0 && fp.config.Verbosity >= config.VerbosityNormal {
+		fmt.Fprintf(fp.config.Out(), "📋 Included files:\n")
 		for _, file := range fp.files {
-			fmt.Printf("   - %s (%d lines)\n", file.FileName, len(file.Lines))
+			if file.Generated {
+				fmt.Fprintf(fp.config.Out(), "   - %s (%d lines, generated)\n", file.RelPath, len(file.Lines))
+			} else {
+				fmt.Fprintf(fp.config.Out(), "   - %s (%d lines)\n", file.RelPath, len(file.Lines))
+			}
+		}
+	}
+
+	if len(generatedFiles) > 0 && fp.config.Verbosity >= config.VerbosityNormal {
+		fmt.Fprintf(fp.config.Out(), "🤖 Generated files:\n")
+		for _, d := range generatedFiles {
+			fmt.Fprintf(fp.config.Out(), "   - %s (%s: %s)\n", d.Path, d.Reason, d.Rule)
+		}
+	}
+
+	if len(fp.longLineFiles) > 0 && fp.config.Verbosity >= config.VerbosityNormal {
+		fmt.Fprintf(fp.config.Out(), "📏 Long lines (long_lines.max_length = %d):\n", fp.config.LongLines.MaxLength)
+		for _, report := range fp.longLineFiles {
+			kind := ""
+			if report.Minified {
+				kind = ", minified"
+			}
+			fmt.Fprintf(fp.config.Out(), "   - %s (%d long lines, longest %d chars%s) → %s\n",
+				report.Path, report.LongLines, report.Longest, kind, report.Action)
+		}
+	}
+
+	// ✅ Luôn liệt kê file không decode được / file nhị phân (kể cả --quiet): nội dung này sẽ thiếu trong tài liệu
+	if len(undecodableFiles) > 0 {
+		fmt.Fprintf(fp.config.Out(), "⚠️  Undecodable / binary files (wrong encoding? see encoding.overrides in copyright.yaml):\n")
+		for _, d := range undecodableFiles {
+			fmt.Fprintf(fp.config.Out(), "   - %s (%s: %s)\n", d.Path, d.Reason, d.Rule)
+		}
+	}
+
+	// ✅ Báo cáo từng file đã bị sửa nội dung khi chuẩn hoá
+	if len(fp.normalizedFiles) > 0 && fp.config.Verbosity >= config.VerbosityNormal {
+		fmt.Fprintf(fp.config.Out(), "🧹 Normalized files:\n")
+		for _, report := range fp.normalizedFiles {
+			fmt.Fprintf(fp.config.Out(), "   - %s (%s)\n", report.Path, report.describe())
 		}
 	}
 
-	fmt.Println(strings.Repeat("=", 70))
+	fmt.Fprintln(fp.config.Out(), strings.Repeat("=", 70))
 }
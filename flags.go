@@ -0,0 +1,323 @@
+// flags.go - Command-line flags shared by all subcommands
+package main
+
+import (
+	"copyright-code-word/config"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ✅ Flag có thể lặp lại (--exclude=a --exclude=b) hoặc phân tách bằng dấu phẩy
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*s = append(*s, item)
+		}
+	}
+	return nil
+}
+
+// ✅ Giá trị flag trước khi ghi lên config; chỉ flag được truyền mới ghi đè
+type cliFlags struct {
+	configPath string
+	profile    string
+
+	linesPerPage         int
+	targetPages          int
+	sectionPages         int
+	minLinesForPageBreak int
+	compactHeaderLines   int
+	fileSeparatorLines   int
+	codeFontSize         int
+	wrapWidth            int
+	marginMM             float64
+	lineSpacing          float64
+	pageBreaks           string
+	highlight            string
+	tabs                 string
+	tabWidth             int
+	trimTrailing         bool
+	shortenThreshold     int
+	pageSize             string
+	excerptStrategy      string
+	excerptSections      int
+	excerptRanges        stringList
+	excerptBoundaries    string
+	extensions           stringList
+	excludeFiles         stringList
+	excludePatterns      stringList
+	excludeRules         stringList
+	legacyPatterns       bool
+	noGitignore          bool
+	noCopyrightIgnore    bool
+
+	redactMode   string
+	onUnredacted string
+	generated    string
+	encoding     string
+	longLines    string
+	controlChars string
+	order        string
+	orderFirst   stringList
+
+	// ✅ Trang bìa
+	cover          bool
+	coverLanguage  string
+	coverSkipPage  bool
+	softwareName   string
+	version        string
+	owner          string
+	authors        stringList
+	completionDate string
+	toc            string
+
+	gitRef     string
+	outputDir  string
+	outputName string
+	pageMap    string
+
+	verbose bool
+	quiet   bool
+
+	// ✅ Báo cáo lý do giữ / loại file
+	format     string
+	explain    string
+	reportJSON string
+}
+
+func newFlagSet(cmd *command, output io.Writer) (*flag.FlagSet, *cliFlags) {
+	defaults := config.LoadConfig()
+	f := &cliFlags{}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: copyright-tool %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&f.configPath, "config", "", "load settings from a YAML/JSON file (default: copyright.yaml/.yml/.json in the scanned directory)")
+	fs.StringVar(&f.profile, "profile", "", "registration profile: "+strings.Join(config.ProfileNames(nil), ", ")+" or one from the config file")
+
+	fs.IntVar(&f.linesPerPage, "lines-per-page", defaults.LinesPerPage, "code lines per page")
+	fs.IntVar(&f.targetPages, "target-pages", defaults.TargetPages, "pages in the shortened document")
+	fs.IntVar(&f.sectionPages, "section-pages", defaults.SectionPages, "pages per excerpt section")
+	fs.IntVar(&f.minLinesForPageBreak, "min-lines-for-page-break", defaults.MinLinesForPageBreak, "smart page break threshold (lines already on the page)")
+	fs.IntVar(&f.compactHeaderLines, "compact-header-lines", defaults.CompactHeaderLines, "lines used by each file header")
+	fs.IntVar(&f.fileSeparatorLines, "file-separator-lines", defaults.FileSeparatorLines, "lines used by each file separator")
+	fs.IntVar(&f.codeFontSize, "code-font-size", defaults.CodeFontSize, "font size of code lines (pt)")
+	fs.IntVar(&f.wrapWidth, "wrap-width", defaults.WrapWidth, "characters per code row before wrapping (0 = derive from page size and font size)")
+	fs.Float64Var(&f.marginMM, "margin-mm", defaults.MarginMM, "page margins on all four sides (mm)")
+	fs.Float64Var(&f.lineSpacing, "line-spacing", defaults.LineSpacing, "code line height as a multiple of the font size (0 = fit exactly lines_per_page)")
+	fs.StringVar(&f.pageBreaks, "page-breaks", defaults.PageBreaks, "page breaks: flow (Word flows the text) or fixed (hard break every lines_per_page rows)")
+	fs.StringVar(&f.highlight, "highlight", defaults.Highlight, "syntax highlighting: "+strings.Join(config.HighlightThemes, ", ")+" (mono is print-friendly)")
+	fs.StringVar(&f.tabs, "tabs", defaults.Indentation.Tabs, "tab characters: expand (to spaces) or tabstop (Word tab stops)")
+	fs.IntVar(&f.tabWidth, "tab-width", defaults.Indentation.TabWidth, "columns between tab stops")
+	fs.BoolVar(&f.trimTrailing, "trim-trailing-whitespace", defaults.Indentation.TrimTrailingWhitespace, "remove whitespace at the end of each line")
+	fs.IntVar(&f.shortenThreshold, "shorten-threshold", defaults.ShortenThresholdPages, "create a shortened document above this many pages (0 = never)")
+	fs.StringVar(&f.pageSize, "page-size", defaults.PageSize, "page size: A4, Letter, Legal")
+	fs.StringVar(&f.excerptStrategy, "excerpt-strategy", defaults.ExcerptStrategy, "shortened document strategy: "+strings.Join(config.ExcerptStrategies, ", "))
+	fs.IntVar(&f.excerptSections, "excerpt-sections", defaults.ExcerptSections, "number of evenly-spaced excerpt sections (0 = target pages / section pages)")
+	fs.Var(&f.excerptRanges, "excerpt-range", "file glob with optional lines for the curated strategy, e.g. Services/OrderService.cs:1-200 (repeatable, replaces excerpt_ranges)")
+	fs.StringVar(&f.excerptBoundaries, "excerpt-boundaries", defaults.ExcerptBoundaries, "where shortened document sections may start and end: "+strings.Join(config.ExcerptBoundaries, ", "))
+	fs.Var(&f.extensions, "extensions", "supported extensions, replaces the default list (e.g. .cs,.dart)")
+	fs.Var(&f.excludeFiles, "exclude", "exclude a file by exact name (repeatable)")
+	fs.Var(&f.excludePatterns, "exclude-pattern", "exclude files whose name contains the pattern (legacy substring match, repeatable)")
+	fs.Var(&f.excludeRules, "exclude-rule", "exclude by path glob (lib/generated/**), regex (re:^src/.*Dto\\.cs$) or re-include with ! (repeatable)")
+	fs.BoolVar(&f.noGitignore, "no-gitignore", false, "do not apply .gitignore files")
+	fs.BoolVar(&f.noCopyrightIgnore, "no-copyrightignore", false, "do not apply .copyrightignore files")
+	fs.BoolVar(&f.legacyPatterns, "legacy-patterns", false, "also apply the old default substring patterns (ip, key, env...)")
+
+	fs.StringVar(&f.generated, "generated", defaults.GeneratedCode.Action, "files with generated-code header markers: exclude, tag (keep and mark) or off")
+	fs.StringVar(&f.encoding, "encoding", defaults.Encoding.Default, "source file encoding: auto (BOM + detection) or a name such as utf-8, utf-16le, windows-1258")
+	fs.StringVar(&f.longLines, "long-lines", defaults.LongLines.Action, "lines longer than long_lines.max_length: wrap, truncate (cut with marker) or exclude the file")
+	fs.StringVar(&f.controlChars, "control-chars", defaults.Normalization.ControlChars, "control characters (form feed, ESC...): escape (as \\x0C) or strip")
+	fs.StringVar(&f.order, "order", defaults.Ordering.Strategy, "file order in the documents: "+strings.Join(config.OrderStrategies, ", "))
+	fs.Var(&f.orderFirst, "order-priority", "glob or path for --order=priority, highest priority first (repeatable)")
+	fs.StringVar(&f.redactMode, "redact", defaults.Redaction.Mode, "secret redaction: redact (mask), report (list only) or off")
+	fs.StringVar(&f.onUnredacted, "on-unredacted", defaults.Redaction.OnUnredacted, "when high-confidence secrets remain unmasked: fail or warn")
+
+	fs.BoolVar(&f.cover, "cover", defaults.Cover.Enabled, "add a cover page with the software registration details (default from the profile)")
+	fs.StringVar(&f.coverLanguage, "cover-language", defaults.Cover.Language, "cover page labels: vi or en")
+	fs.BoolVar(&f.coverSkipPage, "cover-skip-page-number", defaults.Cover.SkipPageNumber, "do not number the cover page (code starts at page 1)")
+	fs.StringVar(&f.softwareName, "software-name", "", "software name on the cover page")
+	fs.StringVar(&f.version, "software-version", "", "software version on the cover page")
+	fs.StringVar(&f.owner, "owner", "", "copyright owner (organisation) on the cover page")
+	fs.Var(&f.authors, "author", "author on the cover page (repeatable or comma-separated)")
+	fs.StringVar(&f.completionDate, "completion-date", "", "completion date on the cover page (YYYY-MM-DD)")
+
+	fs.StringVar(&f.toc, "toc", defaults.TableOfContents, "table of contents after the cover page: "+strings.Join(config.TOCModes, ", ")+" (field = Word TOC, static = computed page numbers)")
+
+	fs.StringVar(&f.gitRef, "git-ref", "", "read files from the local git repository at this tag, branch or commit instead of the working tree")
+	fs.StringVar(&f.outputDir, "output-dir", defaults.OutputDir, "directory for generated .docx files")
+	fs.StringVar(&f.outputName, "output-name", defaults.OutputName, "file name prefix for generated .docx files")
+	fs.StringVar(&f.pageMap, "page-map", defaults.PageMap, "page map (page → file, first/last line) next to each .docx and in preview: "+strings.Join(config.PageMapFormats, ", "))
+
+	if cmd.name == "scan" {
+		fs.StringVar(&f.format, "format", "table", "scan output: table or json (json goes to stdout, progress to stderr)")
+		fs.StringVar(&f.explain, "explain", "", "explain why a single file (path relative to the directory) is included or excluded")
+	}
+	if cmd.name != "config print" {
+		fs.StringVar(&f.reportJSON, "report-json", "", "also write every include/exclude decision to this JSON file")
+	}
+
+	fs.BoolVar(&f.verbose, "verbose", false, "print exclusion lists and page break decisions")
+	fs.BoolVar(&f.verbose, "v", false, "shorthand for --verbose")
+	fs.BoolVar(&f.quiet, "quiet", false, "only print summaries and errors")
+	fs.BoolVar(&f.quiet, "q", false, "shorthand for --quiet")
+
+	return fs, f
+}
+
+// parseInterspersed cho phép flag đứng trước hoặc sau thư mục (generate ./src --exclude=x).
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// applyTo ghi các flag đã được truyền lên config (sau profile và file config).
+func (f *cliFlags) applyTo(cfg *config.Config, fs *flag.FlagSet) {
+	switch {
+	case f.quiet:
+		cfg.Verbosity = config.VerbosityQuiet
+	case f.verbose:
+		cfg.Verbosity = config.VerbosityVerbose
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "lines-per-page":
+			cfg.LinesPerPage = f.linesPerPage
+		case "target-pages":
+			cfg.TargetPages = f.targetPages
+		case "section-pages":
+			cfg.SectionPages = f.sectionPages
+		case "min-lines-for-page-break":
+			cfg.MinLinesForPageBreak = f.minLinesForPageBreak
+		case "compact-header-lines":
+			cfg.CompactHeaderLines = f.compactHeaderLines
+		case "file-separator-lines":
+			cfg.FileSeparatorLines = f.fileSeparatorLines
+		case "code-font-size":
+			cfg.CodeFontSize = f.codeFontSize
+		case "wrap-width":
+			cfg.WrapWidth = f.wrapWidth
+		case "margin-mm":
+			cfg.MarginMM = f.marginMM
+		case "line-spacing":
+			cfg.LineSpacing = f.lineSpacing
+		case "page-breaks":
+			cfg.PageBreaks = f.pageBreaks
+		case "highlight":
+			cfg.Highlight = f.highlight
+		case "tabs":
+			cfg.Indentation.Tabs = f.tabs
+		case "tab-width":
+			cfg.Indentation.TabWidth = f.tabWidth
+		case "trim-trailing-whitespace":
+			cfg.Indentation.TrimTrailingWhitespace = f.trimTrailing
+		case "shorten-threshold":
+			cfg.ShortenThresholdPages = f.shortenThreshold
+		case "page-size":
+			cfg.PageSize = f.pageSize
+		case "excerpt-strategy":
+			cfg.ExcerptStrategy = f.excerptStrategy
+		case "excerpt-sections":
+			cfg.ExcerptSections = f.excerptSections
+		case "excerpt-range":
+			cfg.ExcerptRanges = nil
+			for _, value := range f.excerptRanges {
+				cfg.ExcerptRanges = append(cfg.ExcerptRanges, config.ParseExcerptRange(value))
+			}
+		case "excerpt-boundaries":
+			cfg.ExcerptBoundaries = f.excerptBoundaries
+		case "extensions":
+			cfg.SupportedExtensions = make(map[string]bool)
+			for _, ext := range f.extensions {
+				cfg.AddSupportedExtension(ext)
+			}
+		case "no-gitignore":
+			cfg.RespectGitignore = !f.noGitignore
+		case "no-copyrightignore":
+			cfg.UseCopyrightIgnore = !f.noCopyrightIgnore
+		case "legacy-patterns":
+			cfg.LegacyExcludePatterns = f.legacyPatterns
+		case "generated":
+			cfg.GeneratedCode.Action = f.generated
+		case "encoding":
+			cfg.Encoding.Default = f.encoding
+		case "long-lines":
+			cfg.LongLines.Action = f.longLines
+		case "control-chars":
+			cfg.Normalization.ControlChars = f.controlChars
+		case "order":
+			cfg.Ordering.Strategy = f.order
+		case "order-priority":
+			cfg.Ordering.Priority = append([]string{}, f.orderFirst...)
+		case "redact":
+			cfg.Redaction.Mode = f.redactMode
+		case "on-unredacted":
+			cfg.Redaction.OnUnredacted = f.onUnredacted
+		case "cover":
+			cfg.Cover.Enabled = f.cover
+		case "cover-language":
+			cfg.Cover.Language = f.coverLanguage
+		case "cover-skip-page-number":
+			cfg.Cover.SkipPageNumber = f.coverSkipPage
+		case "software-name":
+			cfg.Cover.SoftwareName = f.softwareName
+		case "software-version":
+			cfg.Cover.Version = f.version
+		case "owner":
+			cfg.Cover.Owner = f.owner
+		case "author":
+			cfg.Cover.Authors = append([]string{}, f.authors...)
+		case "completion-date":
+			cfg.Cover.CompletionDate = f.completionDate
+		case "toc":
+			cfg.TableOfContents = f.toc
+		case "git-ref":
+			cfg.GitRef = f.gitRef
+		case "output-dir":
+			cfg.OutputDir = f.outputDir
+		case "output-name":
+			cfg.OutputName = f.outputName
+		case "page-map":
+			cfg.PageMap = f.pageMap
+		}
+	})
+
+	for _, filename := range f.excludeFiles {
+		cfg.AddExcludeFile(filename)
+		cfg.Logf(config.VerbosityVerbose, "🚫 Added to exclude list: %s\n", filename)
+	}
+	for _, pattern := range f.excludePatterns {
+		cfg.AddExcludePattern(pattern)
+		cfg.Logf(config.VerbosityVerbose, "🚫 Added exclude pattern: *%s*\n", pattern)
+	}
+	for _, rule := range f.excludeRules {
+		cfg.AddExcludeRule(rule)
+		cfg.Logf(config.VerbosityVerbose, "🚫 Added exclude rule: %s\n", rule)
+	}
+}
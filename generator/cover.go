@@ -0,0 +1,124 @@
+// cover.go - Cover page with software registration details (first page of each document)
+package generator
+
+import (
+	"copyright-code-word/models"
+	"fmt"
+	"strings"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/document"
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/wml"
+)
+
+// ✅ Field chưa khai báo được để trống để điền tay khi in
+const coverPlaceholder = "…………………………………………"
+
+// missingCoverFields trả về các key cover.* chưa khai báo (in cảnh báo ở preview / generate).
+func (dg *DocumentGenerator) missingCoverFields() []string {
+	cover := dg.config.Cover
+	if !cover.Enabled {
+		return nil
+	}
+
+	var missing []string
+	fields := []struct {
+		key   string
+		value string
+	}{
+		{"software_name", cover.SoftwareName},
+		{"version", cover.Version},
+		{"owner", cover.Owner},
+		{"authors", strings.Join(cover.Authors, "")},
+		{"completion_date", cover.CompletionDate},
+	}
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, "cover."+field.key)
+		}
+	}
+	return missing
+}
+
+// addCoverPage thêm nội dung trang bìa (section break do addFrontMatter thêm).
+// totalPages là tổng số trang của document này; excerpt = true với file rút gọn.
+func (dg *DocumentGenerator) addCoverPage(doc *document.Document, files []models.CodeFile, totalPages int, excerpt bool) {
+	cover := dg.config.Cover
+	labels := frontMatterLabelSets[cover.Language]
+
+	for i := 0; i < 6; i++ {
+		doc.AddParagraph()
+	}
+	dg.addCoverHeading(doc, cover.Title, 18)
+	dg.addCoverHeading(doc, valueOrPlaceholder(strings.ToUpper(cover.SoftwareName)), 16)
+	doc.AddParagraph()
+	doc.AddParagraph()
+
+	completionDate := ""
+	if date, err := cover.ParseCompletionDate(); err == nil && !date.IsZero() {
+		completionDate = date.Format(labels.DateFormat)
+	}
+	documentKind := labels.FullDocument
+	if excerpt {
+		documentKind = labels.Excerpt
+	}
+
+	totalLines := 0
+	for _, file := range files {
+		totalLines += len(file.Lines)
+	}
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{labels.SoftwareName, cover.SoftwareName},
+		{labels.Version, cover.Version},
+		{labels.Owner, cover.Owner},
+		{labels.Authors, strings.Join(cover.Authors, ", ")},
+		{labels.CompletionDate, completionDate},
+		{labels.Languages, strings.Join(dg.languageNames(files), ", ")},
+		{labels.Files, fmt.Sprintf("%d", len(files))},
+		{labels.Lines, fmt.Sprintf("%d", totalLines)},
+		{labels.Pages, fmt.Sprintf("%d", totalPages)},
+		{labels.Document, documentKind},
+	}
+	for _, row := range rows {
+		dg.addCoverField(doc, row.label, valueOrPlaceholder(row.value))
+	}
+}
+
+func (dg *DocumentGenerator) addCoverHeading(doc *document.Document, text string, size measurement.Distance) {
+	para := doc.AddParagraph()
+	para.Properties().SetAlignment(wml.ST_JcCenter)
+	run := para.AddRun()
+	run.AddText(text)
+	run.Properties().SetBold(true)
+	run.Properties().SetSize(size)
+	run.Properties().SetFontFamily("Arial")
+}
+
+func (dg *DocumentGenerator) addCoverField(doc *document.Document, label, value string) {
+	para := doc.AddParagraph()
+	para.Properties().SetStartIndent(20 * measurement.Millimeter)
+
+	labelRun := para.AddRun()
+	labelRun.AddText(label + ": ")
+	labelRun.Properties().SetBold(true)
+	labelRun.Properties().SetSize(12)
+	labelRun.Properties().SetFontFamily("Arial")
+
+	valueRun := para.AddRun()
+	valueRun.AddText(value)
+	valueRun.Properties().SetSize(12)
+	valueRun.Properties().SetFontFamily("Arial")
+	valueRun.Properties().SetColor(color.Black)
+}
+
+func valueOrPlaceholder(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return coverPlaceholder
+	}
+	return value
+}
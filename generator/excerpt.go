@@ -0,0 +1,168 @@
+// excerpt.go - Shortened document: content chosen by the excerpt strategy, its summary and report
+package generator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"copyright-code-word/paginator"
+	"fmt"
+
+	"github.com/unidoc/unioffice/document"
+)
+
+// excerptPlan là nội dung đã chọn cho file rút gọn và bố cục của nó. Tùy chế độ, nội dung là
+// các đoạn file / dòng (lines, declarations, files, curated) hoặc các trang của file đầy đủ
+// (page_breaks = fixed, nằm sẵn trong layout.Pages).
+type excerptPlan struct {
+	strategy string
+	parts    []paginator.ExcerptPart
+	spans    []paginator.PageSpan
+	layout   paginator.Layout
+}
+
+// planExcerpt chạy ExcerptStrategy rồi cắt theo page_breaks / excerpt_boundaries.
+func (dg *DocumentGenerator) planExcerpt(files []models.CodeFile) (*excerptPlan, error) {
+	strategy := paginator.NewExcerptStrategy(dg.config)
+	selection, err := strategy.Select(files)
+	if err != nil {
+		return nil, fmt.Errorf("excerpt strategy %s: %v", strategy.Name(), err)
+	}
+
+	plan := &excerptPlan{strategy: strategy.Name()}
+	switch {
+	case len(selection.Parts) > 0:
+		plan.parts = selection.Parts
+		plan.layout = dg.paginator.PlaceParts(files, plan.parts)
+	case dg.config.FixedLayout():
+		ranges := dg.paginator.CalculatePageRanges(files)
+		plan.spans = dg.paginator.SelectPageSpans(paginator.LastPage(ranges), selection.Windows)
+		plan.layout = paginator.LayoutFromRanges(paginator.SelectPages(ranges, plan.spans))
+	case dg.config.ExcerptBoundaries != config.ExcerptBoundaryLines:
+		plan.parts = dg.paginator.SelectExcerptParts(files, selection.Windows)
+		plan.layout = dg.paginator.PlaceParts(files, plan.parts)
+	default:
+		plan.parts = dg.paginator.LineRangeParts(files, dg.paginator.SelectLineRanges(files, selection.Windows))
+		plan.layout = dg.paginator.PlaceParts(files, plan.parts)
+	}
+	return plan, nil
+}
+
+// printExcerptPlan in các khoảng file / dòng đã chọn cho file rút gọn.
+func (dg *DocumentGenerator) printExcerptPlan(files []models.CodeFile, plan *excerptPlan) {
+	ranges := dg.sourceRanges(files, plan.layout)
+	included, total := 0, 0
+	for _, r := range ranges {
+		included += r.LastLine - r.FirstLine + 1
+	}
+	for _, file := range files {
+		total += len(file.Lines)
+	}
+
+	fmt.Printf("📝 Shortened sections (%s boundaries):\n", dg.excerptBoundaries())
+	for _, span := range plan.spans {
+		fmt.Printf("   - Full document pages %d-%d\n", span.First, span.Last)
+	}
+	fmt.Printf("   - %d ranges, %d of %d lines (%d omitted)\n", len(ranges), included, total, total-included)
+	fmt.Printf("   - Pages (layout): %d\n", plan.layout.TotalPages)
+	// Các chiến lược khác tự chia target_pages; excerpt_ranges chọn tay thì có thể vượt
+	if dg.config.ExcerptStrategy == config.ExcerptCurated && plan.layout.TotalPages > dg.config.TargetPages {
+		fmt.Printf("⚠️  Curated excerpt_ranges fill %d pages, more than target_pages %d\n",
+			plan.layout.TotalPages, dg.config.TargetPages)
+	}
+	for _, r := range ranges {
+		fmt.Printf("   - %s, lines %d-%d\n", r.File, r.FirstLine, r.LastLine)
+	}
+}
+
+// excerptBoundaries mô tả chỗ cắt của file rút gọn: trang (page_breaks = fixed),
+// khoảng chọn tay (curated) hoặc excerpt_boundaries.
+func (dg *DocumentGenerator) excerptBoundaries() string {
+	switch {
+	case dg.config.FixedLayout():
+		return "pages"
+	case dg.config.ExcerptStrategy == config.ExcerptCurated:
+		return "curated"
+	default:
+		return dg.config.ExcerptBoundaries
+	}
+}
+
+// sourceRanges gộp bản đồ trang của layout thành các khoảng dòng liên tiếp của từng file.
+func (dg *DocumentGenerator) sourceRanges(files []models.CodeFile, layout paginator.Layout) []models.SourceRange {
+	var ranges []models.SourceRange
+	lastFile := -1
+	for _, r := range layout.Pages {
+		if r.StartLine > r.EndLine {
+			continue
+		}
+		// Trang sau có thể lặp lại dòng cuối của trang trước (dòng dài bị chia) hoặc nối tiếp ngay sau nó
+		if n := len(ranges); n > 0 && r.FileIndex == lastFile && r.StartLine <= ranges[n-1].LastLine {
+			ranges[n-1].LastLine = max(ranges[n-1].LastLine, r.EndLine+1)
+			continue
+		}
+		ranges = append(ranges, models.SourceRange{
+			File:      dg.displayPath(files[r.FileIndex]),
+			FirstLine: r.StartLine + 1,
+			LastLine:  r.EndLine + 1,
+		})
+		lastFile = r.FileIndex
+	}
+	return ranges
+}
+
+// ExcerptReport trả về nội dung đã chọn cho file rút gọn (sau PrintPlan), nil nếu chỉ tạo file đầy đủ.
+func (dg *DocumentGenerator) ExcerptReport(files []models.CodeFile) *models.ExcerptReport {
+	if dg.excerpt == nil {
+		return nil
+	}
+	return &models.ExcerptReport{
+		Strategy:    dg.excerpt.strategy,
+		Boundaries:  dg.excerptBoundaries(),
+		TargetPages: dg.config.TargetPages,
+		Pages:       dg.excerpt.layout.TotalPages,
+		Ranges:      dg.sourceRanges(files, dg.excerpt.layout),
+	}
+}
+
+func (dg *DocumentGenerator) createShortenedDocument(files []models.CodeFile) error {
+	plan := dg.excerpt
+	if plan == nil {
+		var err error
+		if plan, err = dg.planExcerpt(files); err != nil {
+			return err
+		}
+	}
+
+	doc := document.New()
+	defer doc.Close()
+
+	dg.setupPage(doc)
+	dg.addFrontMatter(doc, files, plan.layout.Placements, plan.layout.TotalPages, true)
+	switch {
+	case dg.config.FixedLayout():
+		// Mỗi trang trích ra đúng là một trang in
+		dg.addPageRanges(doc, files, plan.layout.Pages)
+	default:
+		dg.addExcerptParts(doc, files, plan.parts)
+	}
+	return dg.saveDocument(doc, "shortened_optimized", files, plan.layout)
+}
+
+// addExcerptParts thêm các đoạn file / dòng, đánh dấu đoạn bắt đầu giữa file và các dòng bị lược bỏ.
+func (dg *DocumentGenerator) addExcerptParts(doc *document.Document, files []models.CodeFile, parts []paginator.ExcerptPart) {
+	for _, part := range parts {
+		file := files[part.FileIndex]
+		if part.OmittedBefore > 0 {
+			dg.addOmittedBanner(doc, part.OmittedBefore)
+		}
+		if part.Continued {
+			dg.addContinuedFileHeader(doc, file, part.StartLine, part.EndLine)
+		} else {
+			dg.addCompactFileHeader(doc, file, part.FileIndex+1, false)
+		}
+		dg.addFileContentRange(doc, file, part.StartLine, part.EndLine, false)
+		if part.Separator {
+			dg.addCompactFileSeparator(doc)
+		}
+	}
+}
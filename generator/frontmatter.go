@@ -0,0 +1,197 @@
+// frontmatter.go - Cover page + table of contents section before the code, and its page numbering
+package generator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"copyright-code-word/paginator"
+	"fmt"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/document"
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/wml"
+)
+
+// ✅ Cỡ chữ của bảng mục lục (pt)
+const tocFontSize = 10
+
+// frontMatterLabels là bộ nhãn của trang bìa và mục lục theo ngôn ngữ (cover.language).
+type frontMatterLabels struct {
+	SoftwareName   string
+	Version        string
+	Owner          string
+	Authors        string
+	CompletionDate string
+	Languages      string
+	Files          string
+	Lines          string
+	Pages          string
+	Document       string
+	FullDocument   string
+	Excerpt        string
+	DateFormat     string
+
+	TOCTitle    string
+	TOCFile     string
+	TOCLanguage string
+	TOCLines    string
+	TOCPage     string
+	TOCUpdate   string // Text tạm của field TOC trước khi Word cập nhật
+}
+
+var frontMatterLabelSets = map[string]frontMatterLabels{
+	"vi": {
+		SoftwareName:   "Tên phần mềm",
+		Version:        "Phiên bản",
+		Owner:          "Chủ sở hữu",
+		Authors:        "Tác giả",
+		CompletionDate: "Ngày hoàn thành",
+		Languages:      "Ngôn ngữ lập trình",
+		Files:          "Số file mã nguồn",
+		Lines:          "Số dòng mã nguồn",
+		Pages:          "Tổng số trang",
+		Document:       "Tài liệu",
+		FullDocument:   "Toàn văn mã nguồn",
+		Excerpt:        "Trích đoạn mã nguồn",
+		DateFormat:     "02/01/2006",
+
+		TOCTitle:    "MỤC LỤC",
+		TOCFile:     "File",
+		TOCLanguage: "Ngôn ngữ",
+		TOCLines:    "Số dòng",
+		TOCPage:     "Trang",
+		TOCUpdate:   "Nhấn F9 để cập nhật mục lục.",
+	},
+	"en": {
+		SoftwareName:   "Software name",
+		Version:        "Version",
+		Owner:          "Copyright owner",
+		Authors:        "Authors",
+		CompletionDate: "Completion date",
+		Languages:      "Programming languages",
+		Files:          "Source files",
+		Lines:          "Lines of code",
+		Pages:          "Total pages",
+		Document:       "Document",
+		FullDocument:   "Full source code",
+		Excerpt:        "Source code excerpt",
+		DateFormat:     "January 2, 2006",
+
+		TOCTitle:    "TABLE OF CONTENTS",
+		TOCFile:     "File",
+		TOCLanguage: "Language",
+		TOCLines:    "Lines",
+		TOCPage:     "Page",
+		TOCUpdate:   "Press F9 to update the table of contents.",
+	},
+}
+
+func (dg *DocumentGenerator) hasFrontMatter() bool {
+	return dg.config.Cover.Enabled || dg.config.TableOfContents != config.TOCOff
+}
+
+// frontMatterNumbered cho biết trang bìa / mục lục có được tính vào số trang ở footer không.
+func (dg *DocumentGenerator) frontMatterNumbered() bool {
+	return dg.hasFrontMatter() && !(dg.config.Cover.Enabled && dg.config.Cover.SkipPageNumber)
+}
+
+// tocPages ước tính số trang của mục lục (tiêu đề + dòng tiêu đề bảng + mỗi file một dòng).
+func (dg *DocumentGenerator) tocPages(entries int) int {
+	if dg.config.TableOfContents == config.TOCOff {
+		return 0
+	}
+	rowsPerPage := max(1, int(dg.config.UsableHeightPoints()/(tocFontSize*config.TextLineSpacing+1)))
+	return (entries + 3 + rowsPerPage - 1) / rowsPerPage
+}
+
+// pageOffset là số trang bìa / mục lục được tính vào số trang ở footer (0 nếu code bắt đầu lại từ 1).
+// entries là số dòng của mục lục.
+func (dg *DocumentGenerator) pageOffset(entries int) int {
+	if !dg.frontMatterNumbered() {
+		return 0
+	}
+	frontPages := dg.tocPages(entries)
+	if dg.config.Cover.Enabled {
+		frontPages++
+	}
+	return frontPages
+}
+
+// addFrontMatter thêm trang bìa, mục lục và section break trước phần code.
+// codePages là số trang code; placements là trang bắt đầu của từng file (tính từ trang code đầu tiên).
+func (dg *DocumentGenerator) addFrontMatter(doc *document.Document, files []models.CodeFile, placements []paginator.FilePlacement, codePages int, excerpt bool) {
+	if !dg.hasFrontMatter() {
+		return
+	}
+
+	pageOffset := dg.pageOffset(len(placements))
+	if dg.config.Cover.Enabled {
+		dg.addCoverPage(doc, files, codePages+pageOffset, excerpt)
+	}
+	if dg.config.TableOfContents != config.TOCOff {
+		if dg.config.Cover.Enabled {
+			doc.AddParagraph().AddRun().AddPageBreak()
+		}
+		dg.addTableOfContents(doc, files, placements, pageOffset)
+	}
+
+	// ✅ Section riêng cho phần đầu: không đánh số thì không gắn footer
+	frontSection := doc.AddParagraph().Properties().AddSection(wml.ST_SectionMarkNextPage)
+	dg.setupSection(frontSection)
+	if dg.frontMatterNumbered() {
+		if footer, ok := doc.BodySection().GetFooter(wml.ST_HdrFtrDefault); ok {
+			frontSection.SetFooter(footer, wml.ST_HdrFtrDefault)
+		}
+		return
+	}
+
+	// Trang code đầu tiên bắt đầu lại từ 1
+	start := int64(1)
+	body := doc.BodySection().X()
+	body.PgNumType = wml.NewCT_PageNumber()
+	body.PgNumType.StartAttr = &start
+}
+
+// addTableOfContents thêm mục lục: field TOC của Word (từ outline level của header file) hoặc bảng tính sẵn.
+func (dg *DocumentGenerator) addTableOfContents(doc *document.Document, files []models.CodeFile, placements []paginator.FilePlacement, pageOffset int) {
+	labels := frontMatterLabelSets[dg.config.Cover.Language]
+	dg.addCoverHeading(doc, labels.TOCTitle, 14)
+	doc.AddParagraph()
+
+	if dg.config.TableOfContents == config.TOCField {
+		tocRun := doc.AddParagraph().AddRun()
+		tocRun.AddFieldWithFormatting(`TOC \u \h \z`, "", true)
+		tocRun.AddText(labels.TOCUpdate)
+		doc.Settings.SetUpdateFieldsOnOpen(true)
+		return
+	}
+
+	table := doc.AddTable()
+	table.Properties().SetWidthPercent(100)
+	table.Properties().Borders().SetAll(wml.ST_BorderSingle, color.LightGray, 0.5*measurement.Point)
+
+	header := table.AddRow()
+	for _, title := range []string{"#", labels.TOCFile, labels.TOCLanguage, labels.TOCLines, labels.TOCPage} {
+		addTOCCell(header, title, true)
+	}
+	for i, placement := range placements {
+		file := files[placement.FileIndex]
+		row := table.AddRow()
+		addTOCCell(row, fmt.Sprintf("%d", i+1), false)
+		addTOCCell(row, dg.displayPath(file), false)
+		addTOCCell(row, dg.languageName(file), false)
+		addTOCCell(row, fmt.Sprintf("%d", len(file.Lines)), false)
+		addTOCCell(row, fmt.Sprintf("%d", placement.StartPage+pageOffset), false)
+	}
+}
+
+func addTOCCell(row document.Row, text string, bold bool) {
+	run := row.AddCell().AddParagraph().AddRun()
+	run.AddText(text)
+	run.Properties().SetFontFamily("Arial")
+	run.Properties().SetSize(tocFontSize)
+	if bold {
+		run.Properties().SetBold(true)
+	}
+}
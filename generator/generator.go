@@ -2,6 +2,7 @@ package generator
 
 import (
 	"copyright-code-word/config"
+	"copyright-code-word/highlighter"
 	"copyright-code-word/models"
 	"copyright-code-word/paginator"
 	"fmt"
@@ -17,18 +18,32 @@ import (
 	"github.com/unidoc/unioffice/schema/soo/wml"
 )
 
+// ✅ Cột số dòng của hàng tiếp nối (cùng độ rộng với "%4d │ ")
+const continuationGutter = "   ↪ │ "
+
 type DocumentGenerator struct {
-	config    *config.Config
-	paginator *paginator.Paginator
+	config      *config.Config
+	paginator   *paginator.Paginator
+	highlighter *highlighter.Highlighter
+	source      models.SourceInfo
+	excerpt     *excerptPlan                     // ✅ Nội dung file rút gọn (PrintPlan), nil nếu không cần
+	highlighted map[string][][]highlighter.Token // ✅ Token theo RelPath (highlightLines)
 }
 
 func New(cfg *config.Config) *DocumentGenerator {
 	return &DocumentGenerator{
-		config:    cfg,
-		paginator: paginator.New(cfg),
+		config:      cfg,
+		paginator:   paginator.New(cfg),
+		highlighter: highlighter.New(cfg),
+		highlighted: make(map[string][][]highlighter.Token),
 	}
 }
 
+// ✅ Nguồn của code (thư mục, git ref, commit) để ghi vào metadata của file Word
+func (dg *DocumentGenerator) SetSource(source models.SourceInfo) {
+	dg.source = source
+}
+
 func (dg *DocumentGenerator) InitializeLicense() error {
 	apiKey, err := config.GetAPIKey()
 	if err != nil {
@@ -45,66 +60,94 @@ func (dg *DocumentGenerator) InitializeLicense() error {
 }
 
 func (dg *DocumentGenerator) GenerateDocuments(files []models.CodeFile) error {
-	if len(files) == 0 {
-		return fmt.Errorf("no .cs or .dart files found")
+	needsShortened, err := dg.PrintPlan(files)
+	if err != nil {
+		return err
 	}
 
-	totalPages := dg.paginator.CalculateTotalPages(files)
-	dg.printStatistics(files, totalPages)
-
-	if totalPages <= 100 {
-		fmt.Printf("✅ ≤100 pages - Creating full document\n")
-		return dg.createFullDocument(files)
-	} else {
-		fmt.Printf("⚠️  >100 pages - Creating 2 documents:\n")
-		fmt.Printf("   - Full: %d pages\n", totalPages)
-		fmt.Printf("   - Shortened: %d pages\n", dg.config.TargetPages)
-
-		if err := dg.createFullDocument(files); err != nil {
-			return err
-		}
+	if err := dg.createFullDocument(files); err != nil {
+		return err
+	}
+	if needsShortened {
 		return dg.createShortenedDocument(files)
 	}
+	return nil
 }
 
-func (dg *DocumentGenerator) createFullDocument(files []models.CodeFile) error {
-	doc := document.New()
-	defer doc.Close()
+// ✅ In thống kê + kế hoạch tạo document (dùng cho cả `preview` lẫn `generate`)
+func (dg *DocumentGenerator) PrintPlan(files []models.CodeFile) (needsShortened bool, err error) {
+	if len(files) == 0 {
+		return false, fmt.Errorf("no %s files found", dg.config.Languages.Describe(dg.config.SupportedExtensions))
+	}
 
-	dg.setupPage(doc)
-	dg.addAllFiles(doc, files)
+	// ✅ Số trang theo layout (lề, cỡ chữ, chiều cao dòng) quyết định việc tạo file rút gọn
+	totalPages := dg.paginator.LayoutPages(files)
+	dg.printStatistics(files, totalPages)
+	dg.printPageSelfCheck(files, totalPages)
+	if missing := dg.missingCoverFields(); len(missing) > 0 {
+		fmt.Printf("⚠️  Cover page: %s not set (left blank to fill in by hand)\n", strings.Join(missing, ", "))
+	}
 
-	return dg.saveDocument(doc, "full_optimized")
+	threshold := dg.config.ShortenThresholdPages
+	if threshold == 0 || totalPages <= threshold {
+		fmt.Printf("✅ Profile %s: %d pages - Creating full document\n", dg.config.Profile, totalPages)
+		return false, nil
+	}
+
+	// ✅ Chọn nội dung file rút gọn ngay khi lập kế hoạch để preview / verify in đúng các đoạn sẽ được tạo
+	plan, err := dg.planExcerpt(files)
+	if err != nil {
+		return false, err
+	}
+	dg.excerpt = plan
+
+	fmt.Printf("⚠️  >%d pages (profile %s) - Creating 2 documents:\n", threshold, dg.config.Profile)
+	fmt.Printf("   - Full: %d pages\n", totalPages)
+	fmt.Printf("   - Shortened: %d pages (%s)\n", dg.config.TargetPages, plan.strategy)
+	dg.printExcerptPlan(files, plan)
+	return true, nil
 }
 
-func (dg *DocumentGenerator) createShortenedDocument(files []models.CodeFile) error {
+func (dg *DocumentGenerator) createFullDocument(files []models.CodeFile) error {
 	doc := document.New()
 	defer doc.Close()
 
 	dg.setupPage(doc)
 
-	firstSection, middleStart, middleEnd, lastStart, totalLines := dg.paginator.CalculateContentSections(files)
+	// ✅ Cùng một bố cục cho mục lục, nội dung và bản đồ trang
+	layout := dg.paginator.FullLayout(files)
+	dg.addFrontMatter(doc, files, layout.Placements, layout.TotalPages, false)
+	if dg.config.FixedLayout() {
+		dg.addPageRanges(doc, files, layout.Pages)
+	} else {
+		dg.addAllFiles(doc, files, layout.Placements)
+	}
 
-	fmt.Printf("📝 Shortened sections:\n")
-	fmt.Printf("   - Total content: %d lines\n", totalLines)
-	fmt.Printf("   - First: lines 1-%d\n", firstSection)
-	fmt.Printf("   - Middle: lines %d-%d\n", middleStart+1, middleEnd)
-	fmt.Printf("   - Last: lines %d-%d\n", lastStart+1, totalLines)
+	return dg.saveDocument(doc, "full_optimized", files, layout)
+}
 
-	dg.addContentByLineRange(doc, files, 0, firstSection-1)
-	dg.addContentByLineRange(doc, files, middleStart, middleEnd-1)
-	dg.addContentByLineRange(doc, files, lastStart, totalLines-1)
+// addPageRanges thêm nội dung theo mô hình trang của paginator, ngắt trang cứng khi sang trang mới.
+func (dg *DocumentGenerator) addPageRanges(doc *document.Document, files []models.CodeFile, ranges []models.PageRange) {
+	for i, r := range ranges {
+		file := files[r.FileIndex]
+		pageBreakBefore := i > 0 && r.Page != ranges[i-1].Page
 
-	return dg.saveDocument(doc, "shortened_optimized")
+		if r.Header {
+			dg.addCompactFileHeader(doc, file, r.FileIndex+1, pageBreakBefore)
+			pageBreakBefore = false
+		}
+		if r.StartLine <= r.EndLine {
+			dg.addFileContentRange(doc, file, r.StartLine, r.EndLine, pageBreakBefore)
+		}
+		if r.Separator {
+			dg.addCompactFileSeparator(doc)
+		}
+	}
 }
 
 func (dg *DocumentGenerator) setupPage(doc *document.Document) {
 	section := doc.BodySection()
-	section.SetPageSizeAndOrientation(
-		measurement.Distance(210)*measurement.Millimeter,
-		measurement.Distance(297)*measurement.Millimeter,
-		wml.ST_PageOrientationPortrait,
-	)
+	dg.setupSection(section)
 
 	// Thêm dòng này để có số trang ở góc phải
 	dg.addPageNumberFooter(doc, section)
@@ -142,9 +185,13 @@ func (dg *DocumentGenerator) addPageNumberFooter(doc *document.Document, section
 	separatorRun.Properties().SetSize(10)
 	separatorRun.Properties().SetColor(color.Gray)
 
-	// Thêm tổng số trang
+	// Thêm tổng số trang (trang bìa không đánh số thì chỉ đếm trang của section code)
+	totalPagesField := document.FieldNumberOfPages
+	if dg.hasFrontMatter() && !dg.frontMatterNumbered() {
+		totalPagesField = "SECTIONPAGES"
+	}
 	totalPagesRun := footerPara.AddRun()
-	totalPagesRun.AddFieldWithFormatting(document.FieldNumberOfPages, "", false)
+	totalPagesRun.AddFieldWithFormatting(totalPagesField, "", false)
 	totalPagesRun.Properties().SetFontFamily("Arial")
 	totalPagesRun.Properties().SetSize(10)
 	totalPagesRun.Properties().SetColor(color.Gray)
@@ -153,112 +200,96 @@ func (dg *DocumentGenerator) addPageNumberFooter(doc *document.Document, section
 	section.SetFooter(footer, wml.ST_HdrFtrDefault)
 }
 
-func (dg *DocumentGenerator) addAllFiles(doc *document.Document, files []models.CodeFile) {
-	currentPageLines := 0
-
+// addAllFiles thêm toàn bộ file; smart page break theo paginator.PlaceFiles.
+func (dg *DocumentGenerator) addAllFiles(doc *document.Document, files []models.CodeFile, placements []paginator.FilePlacement) {
 	for i, file := range files {
-		fileHeaderLines := dg.config.CompactHeaderLines
-		fileSeparatorLines := dg.config.FileSeparatorLines
-		if i == len(files)-1 {
-			fileSeparatorLines = 0
+		// Smart page break (đặt trên header để không sinh thêm đoạn trống ở đầu trang mới)
+		if placements[i].BreakBefore {
+			dg.config.Logf(config.VerbosityVerbose, "🔄 Smart page break before %s\n", dg.displayPath(file))
 		}
-		totalFileLinesNeeded := fileHeaderLines + len(file.Lines) + fileSeparatorLines
 
-		// Smart page break
-		if currentPageLines > dg.config.MinLinesForPageBreak &&
-			currentPageLines+totalFileLinesNeeded > dg.config.LinesPerPage {
-
-			breakPara := doc.AddParagraph()
-			breakRun := breakPara.AddRun()
-			breakRun.AddPageBreak()
-			currentPageLines = 0
-
-			fmt.Printf("🔄 Smart page break before %s\n", file.FileName)
-		}
-
-		dg.addFileToDocument(doc, file, i+1)
-		currentPageLines += totalFileLinesNeeded
+		dg.addCompactFileHeader(doc, file, i+1, placements[i].BreakBefore)
+		dg.addFileContentRange(doc, file, 0, len(file.Lines)-1, false)
 
 		if i < len(files)-1 {
 			dg.addCompactFileSeparator(doc)
 		}
-
-		if currentPageLines >= dg.config.LinesPerPage {
-			currentPageLines = currentPageLines % dg.config.LinesPerPage
-		}
 	}
 }
 
-func (dg *DocumentGenerator) addContentByLineRange(doc *document.Document, files []models.CodeFile, globalStartLine, globalEndLine int) {
-	currentGlobalLine := 0
-
-	for i, file := range files {
-		fileStartLine := currentGlobalLine
-		fileHeaderLines := dg.config.CompactHeaderLines
-		fileContentLines := len(file.Lines)
-		fileSeparatorLines := dg.config.FileSeparatorLines
-		if i == len(files)-1 {
-			fileSeparatorLines = 0
-		}
-
-		fileEndLine := fileStartLine + fileHeaderLines + fileContentLines + fileSeparatorLines - 1
-
-		if fileEndLine >= globalStartLine && fileStartLine <= globalEndLine {
-			fileLocalStartLine := 0
-			if globalStartLine > fileStartLine+fileHeaderLines {
-				fileLocalStartLine = globalStartLine - fileStartLine - fileHeaderLines
-			}
-
-			fileLocalEndLine := len(file.Lines) - 1
-			if globalEndLine < fileStartLine+fileHeaderLines+fileContentLines-1 {
-				fileLocalEndLine = globalEndLine - fileStartLine - fileHeaderLines
-			}
-
-			if globalStartLine <= fileStartLine+fileHeaderLines {
-				dg.addCompactFileHeader(doc, file, i+1)
-			}
-
-			if fileLocalStartLine <= fileLocalEndLine && fileLocalEndLine >= 0 && fileLocalStartLine < len(file.Lines) {
-				dg.addFileContentRange(doc, file, max(0, fileLocalStartLine), min(len(file.Lines)-1, fileLocalEndLine))
-			}
-
-			if i < len(files)-1 && globalEndLine >= fileEndLine-fileSeparatorLines {
-				dg.addCompactFileSeparator(doc)
-			}
-		}
-
-		currentGlobalLine = fileEndLine + 1
+func (dg *DocumentGenerator) addCompactFileHeader(doc *document.Document, file models.CodeFile, fileNumber int, pageBreakBefore bool) {
+	fileHeader := doc.AddParagraph()
+	// ✅ page_breaks = fixed: header là một đoạn cao đúng compact_header_lines hàng code
+	if dg.config.FixedLayout() {
+		setLineHeight(fileHeader, float64(dg.config.CompactHeaderLines)*dg.config.CodeLineHeight())
+	} else {
+		setLineHeight(fileHeader, dg.config.HeaderLineHeight())
 	}
-}
+	if pageBreakBefore {
+		fileHeader.Properties().SetPageBreakBefore(true)
+	}
+	// ✅ Outline level 1 để field TOC của Word lấy header làm mục lục (không đổi font / khoảng cách như style Heading)
+	if dg.config.TableOfContents == config.TOCField {
+		fileHeader.Properties().X().OutlineLvl = &wml.CT_DecimalNumber{ValAttr: 0}
+	}
+	fileRun := fileHeader.AddRun()
+	language := dg.languageName(file)
+	if file.Generated {
+		language += ", generated"
+	}
+	fileRun.AddText(fmt.Sprintf("📄 %s (%s, %d lines)",
+		dg.displayPath(file),
+		language,
+		len(file.Lines)))
+	fileRun.Properties().SetBold(true)
+	fileRun.Properties().SetSize(config.HeaderFontSize)
+	fileRun.Properties().SetColor(color.Blue)
 
-func (dg *DocumentGenerator) addFileToDocument(doc *document.Document, file models.CodeFile, fileNumber int) {
-	dg.addCompactFileHeader(doc, file, fileNumber)
-	dg.addFileContentRange(doc, file, 0, len(file.Lines)-1)
+	if !dg.config.FixedLayout() {
+		setLineHeight(doc.AddParagraph(), dg.config.CodeLineHeight())
+	}
 }
 
-func (dg *DocumentGenerator) addCompactFileHeader(doc *document.Document, file models.CodeFile, fileNumber int) {
+// addContinuedFileHeader thêm header cho đoạn bắt đầu giữa file: "(continued) path, lines X–Y".
+func (dg *DocumentGenerator) addContinuedFileHeader(doc *document.Document, file models.CodeFile, startLine, endLine int) {
 	fileHeader := doc.AddParagraph()
+	setLineHeight(fileHeader, dg.config.HeaderLineHeight())
 	fileRun := fileHeader.AddRun()
-	fileRun.AddText(fmt.Sprintf("📄 %s (%s, %d lines)",
-		file.FileName,
-		strings.ToUpper(file.Extension[1:]),
-		len(file.Lines)))
+	fileRun.AddText(fmt.Sprintf("📄 (continued) %s, lines %d–%d", dg.displayPath(file), startLine+1, endLine+1))
 	fileRun.Properties().SetBold(true)
-	fileRun.Properties().SetSize(11)
+	fileRun.Properties().SetSize(config.HeaderFontSize)
 	fileRun.Properties().SetColor(color.Blue)
 
-	doc.AddParagraph()
+	setLineHeight(doc.AddParagraph(), dg.config.CodeLineHeight())
+}
+
+// addOmittedBanner đánh dấu khoảng bị lược bỏ giữa hai đoạn trích (cao một hàng code).
+func (dg *DocumentGenerator) addOmittedBanner(doc *document.Document, lines int) {
+	bannerPara := doc.AddParagraph()
+	setLineHeight(bannerPara, dg.config.CodeLineHeight())
+	bannerPara.Properties().SetAlignment(wml.ST_JcCenter)
+	bannerRun := bannerPara.AddRun()
+	bannerRun.AddText(fmt.Sprintf("… %d lines omitted …", lines))
+	bannerRun.Properties().SetItalic(true)
+	bannerRun.Properties().SetSize(measurement.Distance(dg.config.CodeFontSize))
+	bannerRun.Properties().SetColor(color.Gray)
 }
 
 func (dg *DocumentGenerator) addCompactFileSeparator(doc *document.Document) {
 	separatorPara := doc.AddParagraph()
+	if dg.config.FixedLayout() {
+		setLineHeight(separatorPara, float64(dg.config.FileSeparatorLines)*dg.config.CodeLineHeight())
+	} else {
+		setLineHeight(separatorPara, dg.config.SeparatorLineHeight())
+	}
 	separatorRun := separatorPara.AddRun()
 	separatorRun.AddText(strings.Repeat("─", 60))
-	separatorRun.Properties().SetSize(8)
+	separatorRun.Properties().SetSize(config.SeparatorFontSize)
 	separatorRun.Properties().SetColor(color.LightGray)
 }
 
-func (dg *DocumentGenerator) addFileContentRange(doc *document.Document, file models.CodeFile, startLine, endLine int) {
+// addFileContentRange thêm các dòng [startLine, endLine]; pageBreakBefore đặt ngắt trang trước hàng đầu tiên.
+func (dg *DocumentGenerator) addFileContentRange(doc *document.Document, file models.CodeFile, startLine, endLine int, pageBreakBefore bool) {
 	if startLine < 0 {
 		startLine = 0
 	}
@@ -266,39 +297,103 @@ func (dg *DocumentGenerator) addFileContentRange(doc *document.Document, file mo
 		endLine = len(file.Lines) - 1
 	}
 
+	fontSize := measurement.Distance(dg.config.CodeFontSize)
+	highlighted := dg.highlightLines(file)
 	for lineNum := startLine; lineNum <= endLine; lineNum++ {
-		line := file.Lines[lineNum]
-
-		codePara := doc.AddParagraph()
+		// ✅ Dòng dài được chia thành nhiều hàng: hàng tiếp nối có dấu ↪, không có số dòng mới
+		rows := paginator.WrapLine(file.Lines[lineNum], dg.config.CodeWrapWidth(), dg.config.Indentation.TabWidth)
+		var rowTokens [][]highlighter.Token
+		if highlighted != nil {
+			rowTokens = highlighter.SplitRows(highlighted[lineNum], rows)
+		}
+		for rowIndex, row := range rows {
+			codePara := doc.AddParagraph()
+			setLineHeight(codePara, dg.config.CodeLineHeight())
+			if pageBreakBefore {
+				codePara.Properties().SetPageBreakBefore(true)
+				pageBreakBefore = false
+			}
+			if strings.ContainsRune(row, '\t') {
+				dg.addCodeTabStops(codePara)
+			}
 
-		// Line number
-		lineNumRun := codePara.AddRun()
-		lineNumRun.AddText(fmt.Sprintf("%4d │ ", lineNum+1))
-		lineNumRun.Properties().SetFontFamily("Consolas")
-		lineNumRun.Properties().SetSize(9)
-		lineNumRun.Properties().SetColor(color.Gray)
+			// Line number
+			gutter := fmt.Sprintf("%4d │ ", lineNum+1)
+			if rowIndex > 0 {
+				gutter = continuationGutter
+			}
+			lineNumRun := codePara.AddRun()
+			lineNumRun.AddText(gutter)
+			lineNumRun.Properties().SetFontFamily(config.CodeFontFamily)
+			lineNumRun.Properties().SetSize(fontSize)
+			lineNumRun.Properties().SetColor(color.Gray)
+
+			// Line content (tô màu theo token nếu bật highlight)
+			var tokens []highlighter.Token
+			if rowTokens != nil {
+				tokens = rowTokens[rowIndex]
+			}
+			dg.addCodeRuns(codePara, row, tokens)
+		}
+	}
+}
 
-		// Line content
-		if len(line) > 120 {
-			line = line[:120] + "..."
+// addCodeText ghi text với xml:space="preserve" để Word giữ nguyên khoảng trắng đầu, giữa và cuối dòng.
+// Tab (indentation.tabs = tabstop) được ghi thành <w:tab/>.
+func addCodeText(run document.Run, text string) {
+	for i, part := range strings.Split(text, "\t") {
+		if i > 0 {
+			run.AddTab()
+		}
+		if part == "" {
+			continue
 		}
+		preserve := "preserve"
+		inner := wml.NewEG_RunInnerContent()
+		inner.T = wml.NewCT_Text()
+		inner.T.SpaceAttr = &preserve
+		inner.T.Content = part
+		run.X().EG_RunInnerContent = append(run.X().EG_RunInnerContent, inner)
+	}
+}
+
+// addCodeTabStops đặt tab stop mỗi tab_width ký tự, tính từ sau cột số dòng.
+func (dg *DocumentGenerator) addCodeTabStops(para document.Paragraph) {
+	charWidth := dg.config.CodeCharWidthPoints()
+	tabWidth := dg.config.Indentation.TabWidth
+	for column := tabWidth; column <= dg.config.CodeWrapWidth(); column += tabWidth {
+		position := measurement.Distance(float64(config.LineNumberColumns+column)*charWidth) * measurement.Point
+		para.Properties().AddTabStop(position, wml.ST_TabJcLeft, wml.ST_TabTlcNone)
+	}
+}
 
-		codeRun := codePara.AddRun()
-		codeRun.AddText(line)
-		codeRun.Properties().SetFontFamily("Consolas")
-		codeRun.Properties().SetSize(9)
-		codeRun.Properties().SetColor(color.Black)
+// ✅ Ghi nguồn code vào document properties (File > Info trong Word)
+func (dg *DocumentGenerator) setDocumentMetadata(doc *document.Document) {
+	doc.CoreProperties.SetTitle(dg.config.Cover.Title)
+	doc.CoreProperties.SetCategory(dg.config.Profile)
+
+	props := doc.GetOrCreateCustomProperties()
+	props.SetPropertyAsLpwstr("CopyrightProfile", dg.config.Profile)
+	if dg.source.Commit == "" {
+		return
 	}
+
+	props.SetPropertyAsLpwstr("SourceGitRef", dg.source.GitRef)
+	props.SetPropertyAsLpwstr("SourceCommit", dg.source.Commit)
+	doc.CoreProperties.SetDescription(fmt.Sprintf("Source code at %s (commit %s)", dg.source.GitRef, dg.source.Commit))
 }
 
-func (dg *DocumentGenerator) saveDocument(doc *document.Document, docType string) error {
-	outputDir := "copyright_documents"
+// saveDocument lưu file .docx và bản đồ trang của nó (page_map) cạnh file.
+func (dg *DocumentGenerator) saveDocument(doc *document.Document, docType string, files []models.CodeFile, layout paginator.Layout) error {
+	dg.setDocumentMetadata(doc)
+
+	outputDir := dg.config.OutputDir
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
 	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("source_code_%s_%s.docx", docType, timestamp)
+	filename := fmt.Sprintf("%s_%s_%s.docx", dg.config.OutputName, docType, timestamp)
 	filepath := filepath.Join(outputDir, filename)
 
 	if err := doc.SaveToFile(filepath); err != nil {
@@ -306,20 +401,82 @@ func (dg *DocumentGenerator) saveDocument(doc *document.Document, docType string
 	}
 
 	fmt.Printf("✅ Created Word file: %s\n", filepath)
-	return nil
+	return dg.savePageMap(filepath, files, layout)
 }
 
 func (dg *DocumentGenerator) printStatistics(files []models.CodeFile, totalPages int) {
 	fmt.Printf("📊 Statistics (Optimized):\n")
+	if dg.source.Commit != "" {
+		fmt.Printf("   - Source: %s @ %s (commit %s)\n", dg.source.RootDir, dg.source.GitRef, dg.source.Commit)
+	}
 	fmt.Printf("   - Files: %d\n", len(files))
-	fmt.Printf("   - Total pages: %d (%d lines/page)\n", totalPages, dg.config.LinesPerPage)
+	fmt.Printf("   - Total pages: %d (%d rows/page, %dpt code, line height %.2fpt, margins %gmm)\n",
+		totalPages, dg.config.RowsPerPage(), dg.config.CodeFontSize, dg.config.CodeLineHeight(), dg.config.MarginMM)
+	fmt.Printf("   - Languages: %s\n", dg.languageSummary(files))
 	fmt.Printf("   - Details: ")
 	for _, file := range files {
-		fmt.Printf("%s(%dp) ", file.FileName, file.PageCount)
+		fmt.Printf("%s(%dp) ", dg.displayPath(file), file.PageCount)
 	}
 	fmt.Println()
 }
 
+// printPageSelfCheck so sánh số trang ước tính theo lines_per_page với số trang tính theo layout.
+func (dg *DocumentGenerator) printPageSelfCheck(files []models.CodeFile, layoutPages int) {
+	estimate := dg.paginator.CalculateTotalPages(files)
+	fmt.Printf("   - Page self-check: estimate %d (lines_per_page), layout %d\n", estimate, layoutPages)
+	if estimate != layoutPages {
+		fmt.Printf("⚠️  Estimate differs from layout by %+d pages (file headers, separators and page breaks take extra space)\n",
+			layoutPages-estimate)
+	}
+}
+
+// ✅ Đường dẫn tương đối của file (file tạo bằng tay không có RelPath thì dùng tên file)
+func (dg *DocumentGenerator) displayPath(file models.CodeFile) string {
+	if file.RelPath != "" {
+		return file.RelPath
+	}
+	return file.FileName
+}
+
+// ✅ Tên ngôn ngữ của file (file tạo bằng tay không có Language thì tra theo extension)
+func (dg *DocumentGenerator) languageName(file models.CodeFile) string {
+	if file.Language != "" {
+		return file.Language
+	}
+	return dg.config.LanguageFor(file.Extension).Name
+}
+
+// languageNames trả về tên các ngôn ngữ theo thứ tự xuất hiện (không trùng).
+func (dg *DocumentGenerator) languageNames(files []models.CodeFile) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if name := dg.languageName(file); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// languageSummary trả về "C# 12 files/1840 lines, Dart 3 files/210 lines" theo thứ tự xuất hiện.
+func (dg *DocumentGenerator) languageSummary(files []models.CodeFile) string {
+	names := dg.languageNames(files)
+	fileCounts := make(map[string]int)
+	lineCounts := make(map[string]int)
+	for _, file := range files {
+		name := dg.languageName(file)
+		fileCounts[name]++
+		lineCounts[name] += len(file.Lines)
+	}
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %d files/%d lines", name, fileCounts[name], lineCounts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
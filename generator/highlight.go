@@ -0,0 +1,78 @@
+// highlight.go - Themes for syntax-highlighted code runs
+package generator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/highlighter"
+	"copyright-code-word/models"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/document"
+	"github.com/unidoc/unioffice/measurement"
+)
+
+// runStyle là định dạng của một loại token.
+type runStyle struct {
+	color  color.Color
+	bold   bool
+	italic bool
+}
+
+// ✅ color: màu dịu, vẫn đọc được khi in đen trắng; mono: chỉ dùng đậm / nghiêng / xám
+var highlightThemes = map[string]map[highlighter.Kind]runStyle{
+	config.HighlightColor: {
+		highlighter.Plain:   {color: color.Black},
+		highlighter.Keyword: {color: color.RGB(0x00, 0x33, 0xB3), bold: true},
+		highlighter.String:  {color: color.RGB(0xA3, 0x15, 0x15)},
+		highlighter.Comment: {color: color.RGB(0x00, 0x80, 0x00), italic: true},
+		highlighter.Number:  {color: color.RGB(0x09, 0x86, 0x58)},
+	},
+	config.HighlightMono: {
+		highlighter.Plain:   {color: color.Black},
+		highlighter.Keyword: {color: color.Black, bold: true},
+		highlighter.String:  {color: color.RGB(0x40, 0x40, 0x40)},
+		highlighter.Comment: {color: color.RGB(0x60, 0x60, 0x60), italic: true},
+		highlighter.Number:  {color: color.Black},
+	},
+}
+
+// highlightLines trả về token của từng dòng, nil nếu highlight = off.
+// Mỗi file chỉ tokenize một lần: page_breaks = fixed và file rút gọn thêm một file theo nhiều đoạn.
+func (dg *DocumentGenerator) highlightLines(file models.CodeFile) [][]highlighter.Token {
+	if dg.config.Highlight == config.HighlightOff {
+		return nil
+	}
+	tokens, ok := dg.highlighted[file.RelPath]
+	if !ok {
+		tokens = dg.highlighter.Highlight(file)
+		dg.highlighted[file.RelPath] = tokens
+	}
+	return tokens
+}
+
+// addCodeRuns thêm một hàng code vào paragraph: mỗi token một run theo theme (tokens nil = một run đen).
+func (dg *DocumentGenerator) addCodeRuns(para document.Paragraph, row string, tokens []highlighter.Token) {
+	if tokens == nil {
+		tokens = []highlighter.Token{{Text: row, Kind: highlighter.Plain}}
+	}
+
+	theme := highlightThemes[dg.config.Highlight]
+	if theme == nil {
+		theme = highlightThemes[config.HighlightColor]
+	}
+	fontSize := measurement.Distance(dg.config.CodeFontSize)
+	for _, token := range tokens {
+		style := theme[token.Kind]
+		codeRun := para.AddRun()
+		addCodeText(codeRun, token.Text)
+		codeRun.Properties().SetFontFamily(config.CodeFontFamily)
+		codeRun.Properties().SetSize(fontSize)
+		codeRun.Properties().SetColor(style.color)
+		if style.bold {
+			codeRun.Properties().SetBold(true)
+		}
+		if style.italic {
+			codeRun.Properties().SetItalic(true)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// layout.go - Explicit margins and paragraph spacing so Word renders the layout the paginator simulates
+package generator
+
+import (
+	"copyright-code-word/config"
+
+	"github.com/unidoc/unioffice/document"
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/ofc/sharedTypes"
+	"github.com/unidoc/unioffice/schema/soo/wml"
+)
+
+// setupSection đặt khổ giấy và lề (4 cạnh bằng margin_mm, footer cách mép dưới FooterDistanceMM).
+func (dg *DocumentGenerator) setupSection(section document.Section) {
+	pageSize := config.PageSizes[dg.config.PageSize]
+	section.SetPageSizeAndOrientation(
+		measurement.Distance(pageSize.WidthMM)*measurement.Millimeter,
+		measurement.Distance(pageSize.HeightMM)*measurement.Millimeter,
+		wml.ST_PageOrientationPortrait,
+	)
+
+	margin := measurement.Distance(dg.config.MarginMM) * measurement.Millimeter
+	footer := measurement.Distance(config.FooterDistanceMM) * measurement.Millimeter
+	section.SetPageMargins(margin, margin, margin, margin, footer, footer, 0)
+}
+
+// setLineHeight đặt chiều cao exact cho đoạn (pt), bỏ khoảng cách trước / sau và widow control
+// để mỗi đoạn chiếm đúng chiều cao mà paginator đã tính.
+func setLineHeight(para document.Paragraph, height float64) {
+	spacing := para.Properties().Spacing()
+	spacing.SetBefore(0)
+	spacing.SetAfter(0)
+	spacing.SetLineSpacing(measurement.Distance(height)*measurement.Point, wml.ST_LineSpacingRuleExact)
+
+	para.Properties().X().WidowControl = &wml.CT_OnOff{
+		ValAttr: &sharedTypes.ST_OnOff{ST_OnOff1: sharedTypes.ST_OnOff1Off},
+	}
+}
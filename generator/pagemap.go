@@ -0,0 +1,127 @@
+// pagemap.go - Page map (page → file, first line, last line) next to each .docx and in `preview`
+package generator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"copyright-code-word/paginator"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PageMapEntry là một phần của file nằm trên một trang. Page là số trang in ở footer;
+// FirstLine / LastLine tính từ 1, bằng 0 nếu trang chỉ có header (hoặc dòng phân cách) của file.
+type PageMapEntry struct {
+	Page      int    `json:"page"`
+	File      string `json:"file"`
+	FirstLine int    `json:"first_line,omitempty"`
+	LastLine  int    `json:"last_line,omitempty"`
+}
+
+// pageMapEntries đổi bản đồ trang của layout sang số trang in (đã cộng trang bìa / mục lục nếu được đánh số).
+func (dg *DocumentGenerator) pageMapEntries(files []models.CodeFile, layout paginator.Layout) []PageMapEntry {
+	pageOffset := dg.pageOffset(len(layout.Placements))
+	entries := make([]PageMapEntry, 0, len(layout.Pages))
+	for _, r := range layout.Pages {
+		entry := PageMapEntry{Page: r.Page + pageOffset, File: dg.displayPath(files[r.FileIndex])}
+		if r.StartLine <= r.EndLine {
+			entry.FirstLine, entry.LastLine = r.StartLine+1, r.EndLine+1
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// PrintPageMap in bản đồ trang của file Word đầy đủ (preview): một dòng tóm tắt,
+// từng trang chỉ khi --verbose (document vài trăm trang sẽ tràn màn hình).
+func (dg *DocumentGenerator) PrintPageMap(files []models.CodeFile) {
+	if dg.config.PageMap == config.PageMapOff || len(files) == 0 || dg.config.Verbosity < config.VerbosityNormal {
+		return
+	}
+
+	layout := dg.paginator.FullLayout(files)
+	entries := dg.pageMapEntries(files, layout)
+	if dg.config.Verbosity < config.VerbosityVerbose {
+		fmt.Printf("🗺️  Page map (full document): %d entries over %d pages (--verbose to list)\n", len(entries), layout.TotalPages)
+		return
+	}
+
+	fmt.Printf("🗺️  Page map (full document):\n")
+	for _, entry := range entries {
+		lines := "header"
+		if entry.FirstLine > 0 {
+			lines = fmt.Sprintf("lines %d-%d", entry.FirstLine, entry.LastLine)
+		}
+		fmt.Printf("   - Page %4d: %s (%s)\n", entry.Page, entry.File, lines)
+	}
+}
+
+// savePageMap ghi bản đồ trang cạnh file .docx: <tên file>.pagemap.csv / .pagemap.json.
+func (dg *DocumentGenerator) savePageMap(docPath string, files []models.CodeFile, layout paginator.Layout) error {
+	format := dg.config.PageMap
+	if format == config.PageMapOff {
+		return nil
+	}
+
+	entries := dg.pageMapEntries(files, layout)
+	basePath := strings.TrimSuffix(docPath, filepath.Ext(docPath)) + ".pagemap"
+	if format == config.PageMapCSV || format == config.PageMapBoth {
+		if err := writePageMapCSV(basePath+".csv", entries); err != nil {
+			return err
+		}
+		fmt.Printf("🗺️  Page map saved: %s.csv\n", basePath)
+	}
+	if format == config.PageMapJSON || format == config.PageMapBoth {
+		if err := writePageMapJSON(basePath+".json", filepath.Base(docPath), entries); err != nil {
+			return err
+		}
+		fmt.Printf("🗺️  Page map saved: %s.json\n", basePath)
+	}
+	return nil
+}
+
+func writePageMapCSV(path string, entries []PageMapEntry) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create page map: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"page", "file", "first_line", "last_line"})
+	for _, entry := range entries {
+		firstLine, lastLine := "", ""
+		if entry.FirstLine > 0 {
+			firstLine, lastLine = strconv.Itoa(entry.FirstLine), strconv.Itoa(entry.LastLine)
+		}
+		writer.Write([]string{strconv.Itoa(entry.Page), entry.File, firstLine, lastLine})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write page map: %v", err)
+	}
+	return nil
+}
+
+func writePageMapJSON(path, document string, entries []PageMapEntry) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create page map: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(struct {
+		Document string         `json:"document"`
+		Pages    []PageMapEntry `json:"pages"`
+	}{Document: document, Pages: entries}); err != nil {
+		return fmt.Errorf("failed to write page map: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+// pack.go - Packfile (.idx v2 + .pack) reader with ofs/ref delta support
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ✅ Loại object trong packfile
+const (
+	packCommit   = 1
+	packTree     = 2
+	packBlob     = 3
+	packTag      = 4
+	packOfsDelta = 6
+	packRefDelta = 7
+)
+
+var packTypeNames = map[int]string{
+	packCommit: ObjectCommit,
+	packTree:   ObjectTree,
+	packBlob:   ObjectBlob,
+	packTag:    ObjectTag,
+}
+
+// Số object base được giữ lại để giải delta nhanh hơn
+const deltaBaseCacheSize = 256
+
+type packObject struct {
+	objType string
+	data    []byte
+}
+
+type packFile struct {
+	path    string
+	file    *os.File
+	hashes  []string // Đã sắp xếp (theo thứ tự trong .idx)
+	offsets []int64
+	cache   map[int64]packObject
+}
+
+func openPack(idxPath string) (*packFile, error) {
+	idx, err := os.ReadFile(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack index: %v", err)
+	}
+	if len(idx) < 8+256*4 || !bytes.Equal(idx[:4], []byte{0xff, 't', 'O', 'c'}) ||
+		binary.BigEndian.Uint32(idx[4:8]) != 2 {
+		return nil, fmt.Errorf("unsupported pack index (only version 2): %s", idxPath)
+	}
+
+	fanout := idx[8 : 8+256*4]
+	count := int(binary.BigEndian.Uint32(fanout[255*4:]))
+
+	hashStart := 8 + 256*4
+	crcStart := hashStart + count*20
+	offsetStart := crcStart + count*4
+	largeStart := offsetStart + count*4
+	if len(idx) < largeStart {
+		return nil, fmt.Errorf("truncated pack index: %s", idxPath)
+	}
+
+	pack := &packFile{
+		path:    strings.TrimSuffix(idxPath, ".idx") + ".pack",
+		hashes:  make([]string, count),
+		offsets: make([]int64, count),
+		cache:   make(map[int64]packObject),
+	}
+
+	for i := 0; i < count; i++ {
+		pack.hashes[i] = hex.EncodeToString(idx[hashStart+i*20 : hashStart+i*20+20])
+
+		offset := binary.BigEndian.Uint32(idx[offsetStart+i*4:])
+		if offset&0x80000000 != 0 {
+			// Offset > 2GB nằm trong bảng large offset
+			pos := largeStart + int(offset&0x7fffffff)*8
+			if len(idx) < pos+8 {
+				return nil, fmt.Errorf("truncated pack index: %s", idxPath)
+			}
+			pack.offsets[i] = int64(binary.BigEndian.Uint64(idx[pos:]))
+		} else {
+			pack.offsets[i] = int64(offset)
+		}
+	}
+
+	if pack.file, err = os.Open(pack.path); err != nil {
+		return nil, fmt.Errorf("failed to open pack: %v", err)
+	}
+	return pack, nil
+}
+
+func (p *packFile) close() {
+	if p.file != nil {
+		p.file.Close()
+	}
+}
+
+func (p *packFile) offsetOf(hash string) (int64, bool) {
+	i := sort.SearchStrings(p.hashes, hash)
+	if i < len(p.hashes) && p.hashes[i] == hash {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+func (p *packFile) findPrefix(prefix string) []string {
+	var matches []string
+	for i := sort.SearchStrings(p.hashes, prefix); i < len(p.hashes) && strings.HasPrefix(p.hashes[i], prefix); i++ {
+		matches = append(matches, p.hashes[i])
+	}
+	return matches
+}
+
+// readAt đọc object tại offset, tự giải delta (repo dùng cho ref delta ở pack khác).
+func (p *packFile) readAt(offset int64, repo *Repository) (string, []byte, error) {
+	if cached, ok := p.cache[offset]; ok {
+		return cached.objType, cached.data, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+
+	// Header: type (3 bit) + size (varint)
+	b, err := reader.ReadByte()
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: corrupt object at %d: %v", p.path, offset, err)
+	}
+	typeCode := int(b>>4) & 0x7
+	for b&0x80 != 0 {
+		if b, err = reader.ReadByte(); err != nil {
+			return "", nil, fmt.Errorf("%s: corrupt object at %d: %v", p.path, offset, err)
+		}
+	}
+
+	var objType string
+	var data []byte
+
+	switch typeCode {
+	case packCommit, packTree, packBlob, packTag:
+		if data, err = inflate(reader); err != nil {
+			return "", nil, fmt.Errorf("%s: corrupt object at %d: %v", p.path, offset, err)
+		}
+		objType = packTypeNames[typeCode]
+
+	case packOfsDelta:
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", nil, err
+		}
+		distance := int64(b & 0x7f)
+		for b&0x80 != 0 {
+			if b, err = reader.ReadByte(); err != nil {
+				return "", nil, err
+			}
+			distance = ((distance + 1) << 7) | int64(b&0x7f)
+		}
+
+		baseType, base, err := p.readAt(offset-distance, repo)
+		if err != nil {
+			return "", nil, err
+		}
+		delta, err := inflate(reader)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: corrupt delta at %d: %v", p.path, offset, err)
+		}
+		if data, err = applyDelta(base, delta); err != nil {
+			return "", nil, fmt.Errorf("%s: bad delta at %d: %v", p.path, offset, err)
+		}
+		objType = baseType
+
+	case packRefDelta:
+		baseHash := make([]byte, 20)
+		if _, err := io.ReadFull(reader, baseHash); err != nil {
+			return "", nil, err
+		}
+		baseType, base, err := repo.ReadObject(hex.EncodeToString(baseHash))
+		if err != nil {
+			return "", nil, err
+		}
+		delta, err := inflate(reader)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: corrupt delta at %d: %v", p.path, offset, err)
+		}
+		if data, err = applyDelta(base, delta); err != nil {
+			return "", nil, fmt.Errorf("%s: bad delta at %d: %v", p.path, offset, err)
+		}
+		objType = baseType
+
+	default:
+		return "", nil, fmt.Errorf("%s: unknown object type %d at %d", p.path, typeCode, offset)
+	}
+
+	if len(p.cache) >= deltaBaseCacheSize {
+		for key := range p.cache {
+			delete(p.cache, key)
+			break
+		}
+	}
+	p.cache[offset] = packObject{objType: objType, data: data}
+
+	return objType, data, nil
+}
+
+func inflate(reader io.Reader) ([]byte, error) {
+	zr, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// applyDelta áp dụng git delta (copy/insert) lên base.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	pos := 0
+	readSize := func() (int, error) {
+		size, shift := 0, 0
+		for {
+			if pos >= len(delta) {
+				return 0, fmt.Errorf("truncated delta header")
+			}
+			b := delta[pos]
+			pos++
+			size |= int(b&0x7f) << shift
+			shift += 7
+			if b&0x80 == 0 {
+				return size, nil
+			}
+		}
+	}
+
+	baseSize, err := readSize()
+	if err != nil {
+		return nil, err
+	}
+	if baseSize != len(base) {
+		return nil, fmt.Errorf("base size mismatch (%d != %d)", baseSize, len(base))
+	}
+	resultSize, err := readSize()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, resultSize)
+	for pos < len(delta) {
+		op := delta[pos]
+		pos++
+
+		if op&0x80 != 0 {
+			// Copy từ base
+			var offset, size int
+			for i := 0; i < 4; i++ {
+				if op&(1<<i) != 0 {
+					if pos >= len(delta) {
+						return nil, fmt.Errorf("truncated copy op")
+					}
+					offset |= int(delta[pos]) << (8 * i)
+					pos++
+				}
+			}
+			for i := 0; i < 3; i++ {
+				if op&(1<<(4+i)) != 0 {
+					if pos >= len(delta) {
+						return nil, fmt.Errorf("truncated copy op")
+					}
+					size |= int(delta[pos]) << (8 * i)
+					pos++
+				}
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > len(base) {
+				return nil, fmt.Errorf("copy op out of range")
+			}
+			result = append(result, base[offset:offset+size]...)
+		} else if op != 0 {
+			// Insert dữ liệu mới
+			if pos+int(op) > len(delta) {
+				return nil, fmt.Errorf("truncated insert op")
+			}
+			result = append(result, delta[pos:pos+int(op)]...)
+			pos += int(op)
+		} else {
+			return nil, fmt.Errorf("invalid delta opcode 0")
+		}
+	}
+
+	if len(result) != resultSize {
+		return nil, fmt.Errorf("result size mismatch (%d != %d)", len(result), resultSize)
+	}
+	return result, nil
+}
@@ -0,0 +1,469 @@
+// repository.go - Read-only access to a local .git directory (no git binary, no network)
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ✅ Loại object trong git
+const (
+	ObjectCommit = "commit"
+	ObjectTree   = "tree"
+	ObjectBlob   = "blob"
+	ObjectTag    = "tag"
+)
+
+// Repository đọc trực tiếp object/ref trong thư mục .git, không đụng tới working tree.
+type Repository struct {
+	gitDir    string // .git (hoặc thư mục worktree riêng)
+	commonDir string // Nơi chứa objects/ và refs/ dùng chung
+	workTree  string // Thư mục gốc của working tree
+	packs     []*packFile
+}
+
+// ✅ Một entry trong tree
+type TreeEntry struct {
+	Name  string
+	Mode  string
+	Hash  string
+	IsDir bool
+}
+
+// IsRegularFile trả về false cho submodule (160000) và symlink (120000).
+func (e TreeEntry) IsRegularFile() bool {
+	return !e.IsDir && (e.Mode == "100644" || e.Mode == "100755")
+}
+
+// Open tìm .git từ path đi ngược lên các thư mục cha.
+func Open(path string) (*Repository, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			gitDir := candidate
+			if !info.IsDir() {
+				// Worktree / submodule: file ".git" chứa "gitdir: <path>"
+				if gitDir, err = readGitDirFile(candidate); err != nil {
+					return nil, err
+				}
+			}
+			return openGitDir(gitDir, dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("not a git repository (no .git found above %s)", path)
+		}
+		dir = parent
+	}
+}
+
+func readGitDirFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", fmt.Errorf("invalid .git file: %s", path)
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return gitDir, nil
+}
+
+func openGitDir(gitDir, workTree string) (*Repository, error) {
+	repo := &Repository{gitDir: gitDir, commonDir: gitDir, workTree: workTree}
+
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		repo.commonDir = common
+	}
+
+	if _, err := os.Stat(filepath.Join(repo.commonDir, "objects")); err != nil {
+		return nil, fmt.Errorf("invalid git directory %s: %v", gitDir, err)
+	}
+
+	packDir := filepath.Join(repo.commonDir, "objects", "pack")
+	idxFiles, _ := filepath.Glob(filepath.Join(packDir, "*.idx"))
+	sort.Strings(idxFiles)
+	for _, idxPath := range idxFiles {
+		pack, err := openPack(idxPath)
+		if err != nil {
+			return nil, err
+		}
+		repo.packs = append(repo.packs, pack)
+	}
+
+	return repo, nil
+}
+
+// WorkTree trả về thư mục gốc của working tree.
+func (r *Repository) WorkTree() string {
+	return r.workTree
+}
+
+// Close đóng các pack file đang mở.
+func (r *Repository) Close() {
+	for _, pack := range r.packs {
+		pack.close()
+	}
+}
+
+// ResolveRef chuyển tag/branch/hash (đầy đủ hoặc rút gọn) thành hash của commit.
+// Hỗ trợ hậu tố "~N" và "^" (đi theo parent đầu tiên), ví dụ "v1.2.0~1", "HEAD^".
+func (r *Repository) ResolveRef(ref string) (string, error) {
+	name, generations, err := splitAncestry(ref)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := r.peelToCommit(name)
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i < generations; i++ {
+		_, data, err := r.ReadObject(hash)
+		if err != nil {
+			return "", err
+		}
+		parent, ok := headerValue(data, "parent")
+		if !ok {
+			return "", fmt.Errorf("%s: commit %s has no parent", ref, hash[:12])
+		}
+		hash = parent
+	}
+	return hash, nil
+}
+
+// splitAncestry tách "name~2^" thành ("name", 3).
+func splitAncestry(ref string) (string, int, error) {
+	end := strings.IndexAny(ref, "~^")
+	if end < 0 {
+		return ref, 0, nil
+	}
+
+	name, suffix := ref[:end], ref[end:]
+	generations := 0
+	for len(suffix) > 0 {
+		op := suffix[0]
+		suffix = suffix[1:]
+		digits := 0
+		for digits < len(suffix) && suffix[digits] >= '0' && suffix[digits] <= '9' {
+			digits++
+		}
+		n := 1
+		if digits > 0 {
+			fmt.Sscanf(suffix[:digits], "%d", &n)
+			if op == '^' && n != 1 {
+				return "", 0, fmt.Errorf("unsupported revision %q (only first-parent ^ is supported)", ref)
+			}
+		}
+		generations += n
+		suffix = suffix[digits:]
+	}
+	return name, generations, nil
+}
+
+func (r *Repository) peelToCommit(ref string) (string, error) {
+	hash, err := r.resolveName(ref)
+	if err != nil {
+		return "", err
+	}
+
+	// Annotated tag → đi tới commit
+	for depth := 0; depth < 10; depth++ {
+		objType, data, err := r.ReadObject(hash)
+		if err != nil {
+			return "", err
+		}
+		switch objType {
+		case ObjectCommit:
+			return hash, nil
+		case ObjectTag:
+			target, ok := headerValue(data, "object")
+			if !ok {
+				return "", fmt.Errorf("tag %s has no target object", hash)
+			}
+			hash = target
+		default:
+			return "", fmt.Errorf("%s does not point to a commit (got %s)", ref, objType)
+		}
+	}
+	return "", fmt.Errorf("tag chain too deep for %s", ref)
+}
+
+func (r *Repository) resolveName(ref string) (string, error) {
+	candidates := []string{
+		ref,
+		"refs/" + ref,
+		"refs/tags/" + ref,
+		"refs/heads/" + ref,
+		"refs/remotes/" + ref,
+		"refs/remotes/" + ref + "/HEAD",
+	}
+	for _, name := range candidates {
+		if hash, ok := r.readRef(name, 0); ok {
+			return hash, nil
+		}
+	}
+
+	if isHex(ref) && len(ref) >= 4 && len(ref) <= 40 {
+		return r.expandHash(strings.ToLower(ref))
+	}
+
+	return "", fmt.Errorf("unknown revision %q (not a branch, tag or commit hash)", ref)
+}
+
+// readRef đọc ref dạng loose hoặc trong packed-refs, theo dõi symbolic ref.
+func (r *Repository) readRef(name string, depth int) (string, bool) {
+	if depth > 5 {
+		return "", false
+	}
+
+	for _, dir := range []string{r.gitDir, r.commonDir} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			continue
+		}
+		content := strings.TrimSpace(string(data))
+		if strings.HasPrefix(content, "ref:") {
+			return r.readRef(strings.TrimSpace(strings.TrimPrefix(content, "ref:")), depth+1)
+		}
+		if len(content) == 40 && isHex(content) {
+			return strings.ToLower(content), true
+		}
+	}
+
+	file, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// expandHash tìm object duy nhất có hash bắt đầu bằng prefix.
+func (r *Repository) expandHash(prefix string) (string, error) {
+	matches := make(map[string]bool)
+
+	if len(prefix) == 40 {
+		if r.hasObject(prefix) {
+			return prefix, nil
+		}
+		return "", fmt.Errorf("object %s not found", prefix)
+	}
+
+	looseDir := filepath.Join(r.commonDir, "objects", prefix[:2])
+	if entries, err := os.ReadDir(looseDir); err == nil {
+		for _, entry := range entries {
+			hash := prefix[:2] + entry.Name()
+			if strings.HasPrefix(hash, prefix) {
+				matches[hash] = true
+			}
+		}
+	}
+	for _, pack := range r.packs {
+		for _, hash := range pack.findPrefix(prefix) {
+			matches[hash] = true
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown revision %q", prefix)
+	case 1:
+		for hash := range matches {
+			return hash, nil
+		}
+	}
+	return "", fmt.Errorf("short hash %q is ambiguous (%d objects)", prefix, len(matches))
+}
+
+func (r *Repository) hasObject(hash string) bool {
+	if _, err := os.Stat(r.loosePath(hash)); err == nil {
+		return true
+	}
+	for _, pack := range r.packs {
+		if _, ok := pack.offsetOf(hash); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Repository) loosePath(hash string) string {
+	return filepath.Join(r.commonDir, "objects", hash[:2], hash[2:])
+}
+
+// ReadObject trả về loại và nội dung (đã giải nén) của object.
+func (r *Repository) ReadObject(hash string) (string, []byte, error) {
+	if data, err := os.ReadFile(r.loosePath(hash)); err == nil {
+		return parseLooseObject(hash, data)
+	}
+
+	for _, pack := range r.packs {
+		if offset, ok := pack.offsetOf(hash); ok {
+			return pack.readAt(offset, r)
+		}
+	}
+	return "", nil, fmt.Errorf("object %s not found", hash)
+}
+
+func parseLooseObject(hash string, compressed []byte) (string, []byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", nil, fmt.Errorf("corrupt object %s: %v", hash, err)
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("corrupt object %s: %v", hash, err)
+	}
+
+	nul := bytes.IndexByte(raw, 0)
+	if nul < 0 {
+		return "", nil, fmt.Errorf("corrupt object %s: missing header", hash)
+	}
+	header := strings.Fields(string(raw[:nul]))
+	if len(header) != 2 {
+		return "", nil, fmt.Errorf("corrupt object %s: bad header", hash)
+	}
+	return header[0], raw[nul+1:], nil
+}
+
+// CommitTree trả về hash tree gốc của commit.
+func (r *Repository) CommitTree(commitHash string) (string, error) {
+	objType, data, err := r.ReadObject(commitHash)
+	if err != nil {
+		return "", err
+	}
+	if objType != ObjectCommit {
+		return "", fmt.Errorf("%s is a %s, not a commit", commitHash, objType)
+	}
+	tree, ok := headerValue(data, "tree")
+	if !ok {
+		return "", fmt.Errorf("commit %s has no tree", commitHash)
+	}
+	return tree, nil
+}
+
+// ReadTree trả về các entry của tree theo thứ tự lưu trong git.
+func (r *Repository) ReadTree(treeHash string) ([]TreeEntry, error) {
+	objType, data, err := r.ReadObject(treeHash)
+	if err != nil {
+		return nil, err
+	}
+	if objType != ObjectTree {
+		return nil, fmt.Errorf("%s is a %s, not a tree", treeHash, objType)
+	}
+
+	var entries []TreeEntry
+	for len(data) > 0 {
+		space := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if space < 0 || nul < space || len(data) < nul+21 {
+			return nil, fmt.Errorf("corrupt tree %s", treeHash)
+		}
+		mode := string(data[:space])
+		entries = append(entries, TreeEntry{
+			Name:  string(data[space+1 : nul]),
+			Mode:  mode,
+			Hash:  hex.EncodeToString(data[nul+1 : nul+21]),
+			IsDir: mode == "40000" || mode == "040000",
+		})
+		data = data[nul+21:]
+	}
+	return entries, nil
+}
+
+// SubTree đi theo đường dẫn (dùng "/") từ tree gốc, "" = chính tree gốc.
+func (r *Repository) SubTree(rootTree, path string) (string, error) {
+	hash := rootTree
+	for _, part := range strings.Split(path, "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		entries, err := r.ReadTree(hash)
+		if err != nil {
+			return "", err
+		}
+		found := false
+		for _, entry := range entries {
+			if entry.Name == part && entry.IsDir {
+				hash, found = entry.Hash, true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("path %q does not exist at this revision", path)
+		}
+	}
+	return hash, nil
+}
+
+// ReadBlob trả về nội dung file.
+func (r *Repository) ReadBlob(blobHash string) ([]byte, error) {
+	objType, data, err := r.ReadObject(blobHash)
+	if err != nil {
+		return nil, err
+	}
+	if objType != ObjectBlob {
+		return nil, fmt.Errorf("%s is a %s, not a blob", blobHash, objType)
+	}
+	return data, nil
+}
+
+// headerValue đọc giá trị header (vd "tree", "object") của commit/tag.
+func headerValue(data []byte, key string) (string, bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, key+" ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, key+" ")), true
+		}
+	}
+	return "", false
+}
+
+func isHex(s string) bool {
+	for _, ch := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", ch) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,247 @@
+package gitrepo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git chạy lệnh git trong dir với cấu hình cố định (không phụ thuộc ~/.gitconfig), trả về stdout đã trim.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{
+		"-c", "user.name=Test", "-c", "user.email=test@example.com",
+		"-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false", "-c", "init.defaultBranch=main",
+	}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	full := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestRepo tạo repo có vài commit sửa cùng một file lớn (để pack có delta), thư mục con,
+// branch, tag nhẹ và tag annotated.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+
+	var big strings.Builder
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&big, "public int Value%03d() { return %d; }\n", i, i)
+	}
+	writeFile(t, dir, "src/Big.cs", big.String())
+	writeFile(t, dir, "src/Orders/Order.cs", "class Order {}\n")
+	writeFile(t, dir, "README.md", "# test\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "first")
+	git(t, dir, "tag", "v0.1")
+
+	for i := 1; i <= 4; i++ {
+		content := strings.Replace(big.String(), fmt.Sprintf("return %d;", i*10), fmt.Sprintf("return -%d;", i), 1)
+		writeFile(t, dir, "src/Big.cs", content)
+		writeFile(t, dir, fmt.Sprintf("lib/v%d/Feature.dart", i), fmt.Sprintf("// feature %d\n", i))
+		git(t, dir, "add", "-A")
+		git(t, dir, "commit", "-q", "-m", fmt.Sprintf("change %d", i))
+		if i == 2 {
+			git(t, dir, "tag", "-a", "v1.0", "-m", "release 1.0")
+			git(t, dir, "branch", "release")
+		}
+	}
+	return dir
+}
+
+// checkRepository so sánh ResolveRef và cây file của mọi ref với git.
+func checkRepository(t *testing.T, dir string) {
+	t.Helper()
+	repo, err := Open(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	head := git(t, dir, "rev-parse", "HEAD")
+	refs := []string{"HEAD", "main", "release", "v0.1", "v1.0", "refs/tags/v1.0", "HEAD~1", "HEAD^", "v1.0~2", head, head[:10]}
+	for _, ref := range refs {
+		want := git(t, dir, "rev-parse", ref+"^{commit}")
+		got, err := repo.ResolveRef(ref)
+		if err != nil {
+			t.Errorf("ResolveRef(%q): %v", ref, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveRef(%q) = %s, want %s", ref, got, want)
+		}
+	}
+
+	for _, ref := range []string{"HEAD", "v0.1", "v1.0"} {
+		commit, err := repo.ResolveRef(ref)
+		if err != nil {
+			t.Fatalf("ResolveRef(%q): %v", ref, err)
+		}
+		checkTree(t, dir, repo, commit)
+	}
+
+	if _, err := repo.ResolveRef("does-not-exist"); err == nil {
+		t.Errorf("ResolveRef(does-not-exist) = nil error, want error")
+	}
+	if _, err := repo.ResolveRef("v0.1~1"); err == nil {
+		t.Errorf("ResolveRef(v0.1~1) = nil error, want error (root commit)")
+	}
+}
+
+// checkTree đi toàn bộ cây của commit và so với `git ls-tree -r`, nội dung blob so với `git cat-file`.
+func checkTree(t *testing.T, dir string, repo *Repository, commit string) {
+	t.Helper()
+	rootTree, err := repo.CommitTree(commit)
+	if err != nil {
+		t.Fatalf("CommitTree(%s): %v", commit, err)
+	}
+	if want := git(t, dir, "rev-parse", commit+"^{tree}"); rootTree != want {
+		t.Errorf("CommitTree(%s) = %s, want %s", commit, rootTree, want)
+	}
+
+	got := make(map[string]TreeEntry)
+	var walk func(tree, prefix string)
+	walk = func(tree, prefix string) {
+		entries, err := repo.ReadTree(tree)
+		if err != nil {
+			t.Fatalf("ReadTree(%s): %v", tree, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir {
+				walk(entry.Hash, path.Join(prefix, entry.Name))
+				continue
+			}
+			got[path.Join(prefix, entry.Name)] = entry
+		}
+	}
+	walk(rootTree, "")
+
+	want := strings.Split(git(t, dir, "ls-tree", "-r", commit), "\n")
+	if len(got) != len(want) {
+		t.Errorf("commit %s: %d files, git ls-tree has %d", commit[:12], len(got), len(want))
+	}
+	for _, line := range want {
+		// "<mode> blob <hash>\t<path>"
+		meta, name, _ := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		entry, ok := got[name]
+		if !ok {
+			t.Errorf("commit %s: missing %s", commit[:12], name)
+			continue
+		}
+		if entry.Mode != fields[0] || entry.Hash != fields[2] || !entry.IsRegularFile() {
+			t.Errorf("commit %s: %s = %+v, want mode %s hash %s", commit[:12], name, entry, fields[0], fields[2])
+		}
+		blob, err := repo.ReadBlob(entry.Hash)
+		if err != nil {
+			t.Errorf("ReadBlob(%s): %v", name, err)
+			continue
+		}
+		if content := git(t, dir, "cat-file", "blob", entry.Hash); strings.TrimSpace(string(blob)) != content {
+			t.Errorf("commit %s: content of %s differs from git cat-file", commit[:12], name)
+		}
+	}
+
+	subTree, err := repo.SubTree(rootTree, "src/Orders")
+	if err != nil {
+		t.Fatalf("SubTree(src/Orders): %v", err)
+	}
+	if want := git(t, dir, "rev-parse", commit+":src/Orders"); subTree != want {
+		t.Errorf("SubTree(src/Orders) = %s, want %s", subTree, want)
+	}
+	if _, err := repo.SubTree(rootTree, "missing/dir"); err == nil {
+		t.Errorf("SubTree(missing/dir) = nil error, want error")
+	}
+}
+
+func TestLooseObjects(t *testing.T) {
+	dir := newTestRepo(t)
+	checkRepository(t, dir)
+}
+
+func TestPackedWithOffsetDeltas(t *testing.T) {
+	dir := newTestRepo(t)
+	git(t, dir, "repack", "-a", "-d", "-f", "-q", "--depth=10", "--window=10")
+	git(t, dir, "pack-refs", "--all")
+	requirePackedDeltas(t, dir, packOfsDelta)
+	checkRepository(t, dir)
+}
+
+func TestPackedWithRefDeltas(t *testing.T) {
+	dir := newTestRepo(t)
+	git(t, dir, "-c", "repack.useDeltaBaseOffset=false", "repack", "-a", "-d", "-f", "-q", "--depth=10", "--window=10")
+	git(t, dir, "pack-refs", "--all")
+	requirePackedDeltas(t, dir, packRefDelta)
+	checkRepository(t, dir)
+}
+
+// requirePackedDeltas kiểm tra repo không còn loose object và pack có object kiểu deltaType.
+func requirePackedDeltas(t *testing.T, dir string, deltaType byte) {
+	t.Helper()
+	if count := git(t, dir, "count-objects", "-v"); !strings.Contains(count, "count: 0\n") {
+		t.Fatalf("loose objects remain after repack:\n%s", count)
+	}
+	indexes, err := filepath.Glob(filepath.Join(dir, ".git", "objects", "pack", "*.idx"))
+	if err != nil || len(indexes) != 1 {
+		t.Fatalf("want one pack index after repack, got %v", indexes)
+	}
+	types := packObjectTypes(t, indexes[0])
+	if types[deltaType] == 0 {
+		t.Fatalf("pack has no objects of type %d (types: %v)", deltaType, types)
+	}
+}
+
+// packObjectTypes đếm object theo kiểu: offset lấy từ .idx (v2, offset 31 bit), kiểu đọc ở byte đầu trong .pack.
+func packObjectTypes(t *testing.T, indexPath string) map[byte]int {
+	t.Helper()
+	index, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack, err := os.ReadFile(strings.TrimSuffix(indexPath, ".idx") + ".pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) < 8+256*4 || !bytes.Equal(index[:8], []byte{0xff, 't', 'O', 'c', 0, 0, 0, 2}) {
+		t.Fatalf("%s: not a version 2 pack index", indexPath)
+	}
+
+	// Header 8 byte, fanout 256 × 4 (phần tử cuối = số object), rồi hash 20 byte và CRC32 4 byte mỗi object
+	count := int(binary.BigEndian.Uint32(index[8+255*4:]))
+	offsets := index[8+256*4+count*24:]
+	types := make(map[byte]int)
+	for i := 0; i < count; i++ {
+		offset := binary.BigEndian.Uint32(offsets[i*4:])
+		if offset&0x80000000 != 0 {
+			t.Fatalf("%s: 64-bit offsets are not expected in a test pack", indexPath)
+		}
+		types[(pack[offset]>>4)&7]++
+	}
+	return types
+}
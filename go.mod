@@ -4,6 +4,10 @@ go 1.21
 
 require github.com/unidoc/unioffice v1.39.0
 
+require gopkg.in/yaml.v3 v3.0.1
+
+require golang.org/x/text v0.22.0
+
 require (
 	github.com/joho/godotenv v1.5.1
 	github.com/richardlehane/msoleps v1.0.4 // indirect
@@ -0,0 +1,263 @@
+// highlighter.go - Split code lines into keyword / string / comment / number tokens per language
+package highlighter
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind là loại token để chọn màu khi render.
+type Kind int
+
+const (
+	Plain Kind = iota
+	Keyword
+	String
+	Comment
+	Number
+)
+
+// Token là một đoạn liên tiếp của dòng có cùng loại.
+type Token struct {
+	Text string
+	Kind Kind
+}
+
+// Highlighter tách token theo cú pháp của ngôn ngữ trong registry.
+type Highlighter struct {
+	config *config.Config
+}
+
+func New(cfg *config.Config) *Highlighter {
+	return &Highlighter{config: cfg}
+}
+
+// ✅ Cú pháp của một ngôn ngữ, dựng từ config.Language
+type syntax struct {
+	lineComment string
+	blockStart  string
+	blockEnd    string
+	strings     []string // Dấu dài trước (""" trước ")
+	keywords    map[string]bool
+	ignoreCase  bool
+}
+
+// trạng thái mang sang dòng sau: đang ở trong block comment hoặc chuỗi nhiều dòng
+type state struct {
+	kind  Kind // Plain = không mang trạng thái
+	close string
+}
+
+func newSyntax(lang config.Language) syntax {
+	s := syntax{lineComment: lang.LineComment, keywords: make(map[string]bool), ignoreCase: lang.KeywordsIgnoreCase}
+	if len(lang.BlockComment) == 2 {
+		s.blockStart, s.blockEnd = lang.BlockComment[0], lang.BlockComment[1]
+	}
+
+	s.strings = append([]string{}, lang.Strings...)
+	if len(s.strings) == 0 {
+		s.strings = []string{`"`, "'"}
+	}
+	// Dấu dài kiểm tra trước để """ không bị nhận thành "" + "
+	sort.SliceStable(s.strings, func(i, j int) bool {
+		return len(s.strings[i]) > len(s.strings[j])
+	})
+
+	for _, keyword := range lang.Keywords {
+		if s.ignoreCase {
+			keyword = strings.ToLower(keyword)
+		}
+		s.keywords[keyword] = true
+	}
+	return s
+}
+
+// Highlight trả về token của từng dòng; comment / chuỗi nhiều dòng được giữ trạng thái qua các dòng.
+// Nối Text của các token của một dòng luôn bằng đúng dòng đó.
+func (h *Highlighter) Highlight(file models.CodeFile) [][]Token {
+	language := h.config.LanguageFor(file.Extension)
+	s := newSyntax(language)
+
+	result := make([][]Token, len(file.Lines))
+	var current state
+	for i, line := range file.Lines {
+		result[i], current = s.tokenizeLine(line, current)
+	}
+	return result
+}
+
+func (s syntax) tokenizeLine(line string, current state) ([]Token, state) {
+	var tokens []Token
+	add := func(text string, kind Kind) {
+		if text == "" {
+			return
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].Kind == kind {
+			tokens[n-1].Text += text
+			return
+		}
+		tokens = append(tokens, Token{Text: text, Kind: kind})
+	}
+
+	pos := 0
+	// ✅ Tiếp tục block comment / chuỗi nhiều dòng từ dòng trước
+	if current.kind != Plain {
+		end := findClose(line, 0, current.close, current.kind == String)
+		if end < 0 {
+			add(line, current.kind)
+			return tokens, current
+		}
+		add(line[:end], current.kind)
+		pos = end
+		current = state{}
+	}
+
+	for pos < len(line) {
+		rest := line[pos:]
+		switch {
+		case s.lineComment != "" && strings.HasPrefix(rest, s.lineComment):
+			add(rest, Comment)
+			return tokens, current
+
+		case s.blockStart != "" && strings.HasPrefix(rest, s.blockStart):
+			end := findClose(line, pos+len(s.blockStart), s.blockEnd, false)
+			if end < 0 {
+				add(rest, Comment)
+				return tokens, state{kind: Comment, close: s.blockEnd}
+			}
+			add(line[pos:end], Comment)
+			pos = end
+
+		case s.stringDelimiter(rest) != "":
+			delimiter := s.stringDelimiter(rest)
+			end := findClose(line, pos+len(delimiter), delimiter, true)
+			if end < 0 {
+				add(rest, String)
+				// Chỉ """ / ''' / ` được kéo dài qua nhiều dòng; chuỗi thường chưa đóng dừng ở cuối dòng
+				if len(delimiter) == 3 || delimiter == "`" {
+					return tokens, state{kind: String, close: delimiter}
+				}
+				return tokens, current
+			}
+			add(line[pos:end], String)
+			pos = end
+
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			switch {
+			case unicode.IsDigit(r) && !endsWithWordChar(line[:pos]):
+				end := pos + scanNumber(rest)
+				add(line[pos:end], Number)
+				pos = end
+			case isWordStart(r):
+				end := pos + scanWhile(rest, isWordChar)
+				word := line[pos:end]
+				if s.isKeyword(word) {
+					add(word, Keyword)
+				} else {
+					add(word, Plain)
+				}
+				pos = end
+			default:
+				add(rest[:size], Plain)
+				pos += size
+			}
+		}
+	}
+	return tokens, current
+}
+
+func (s syntax) stringDelimiter(rest string) string {
+	for _, delimiter := range s.strings {
+		if strings.HasPrefix(rest, delimiter) {
+			return delimiter
+		}
+	}
+	return ""
+}
+
+func (s syntax) isKeyword(word string) bool {
+	if s.ignoreCase {
+		word = strings.ToLower(word)
+	}
+	return s.keywords[word]
+}
+
+// findClose trả về vị trí ngay sau dấu đóng tính từ from, -1 nếu dòng chưa đóng.
+// escapes = true: bỏ qua ký tự sau dấu \.
+func findClose(line string, from int, close string, escapes bool) int {
+	for i := from; i < len(line); i++ {
+		if escapes && line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(line[i:], close) {
+			return i + len(close)
+		}
+	}
+	return -1
+}
+
+func scanWhile(text string, accept func(rune) bool) int {
+	for i, r := range text {
+		if !accept(r) {
+			return i
+		}
+	}
+	return len(text)
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isWordChar(r rune) bool {
+	return isWordStart(r) || unicode.IsDigit(r)
+}
+
+// scanNumber trả về độ dài số ở đầu text: 42, 3.14, 0xFF, 1_000, 10L, 2.5m
+// ("1.ToString" chỉ lấy "1"; dấu - của 1e-5 được bỏ qua cho đơn giản).
+func scanNumber(text string) int {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c >= '0' && c <= '9', c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c == '.' && i+1 < len(text) && text[i+1] >= '0' && text[i+1] <= '9':
+		default:
+			return i
+		}
+	}
+	return len(text)
+}
+
+func endsWithWordChar(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return text != "" && isWordChar(r)
+}
+
+// SplitRows chia token của một dòng theo các hàng đã wrap (nối các hàng lại bằng đúng dòng).
+func SplitRows(tokens []Token, rows []string) [][]Token {
+	result := make([][]Token, len(rows))
+	tokenIndex, offset := 0, 0
+	for i, row := range rows {
+		remaining := len(row)
+		for remaining > 0 && tokenIndex < len(tokens) {
+			token := tokens[tokenIndex]
+			available := len(token.Text) - offset
+			if available <= remaining {
+				result[i] = append(result[i], Token{Text: token.Text[offset:], Kind: token.Kind})
+				remaining -= available
+				tokenIndex, offset = tokenIndex+1, 0
+				continue
+			}
+			result[i] = append(result[i], Token{Text: token.Text[offset : offset+remaining], Kind: token.Kind})
+			offset += remaining
+			remaining = 0
+		}
+	}
+	return result
+}
@@ -1,40 +1,277 @@
-// main.go - Enhanced version with file exclusion support
+// main.go - Command-line entry point with subcommands
 package main
 
 import (
 	"copyright-code-word/config"
 	"copyright-code-word/fileprocessor"
 	"copyright-code-word/generator"
+	"copyright-code-word/models"
+	"copyright-code-word/redactor"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
 )
 
+// ✅ Một subcommand: tên, cú pháp, mô tả và hàm xử lý
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(rootDir string, cfg *config.Config, flags *cliFlags) error
+}
+
+var commands = []*command{
+	{
+		name:    "scan",
+		args:    "<directory_path> [flags]",
+		summary: "List the files that would be included or excluded, and which rule decided.",
+		run:     runScan,
+	},
+	{
+		name:    "preview",
+		args:    "<directory_path> [flags]",
+		summary: "Scan and print page statistics and which documents would be created, without writing anything.",
+		run:     runPreview,
+	},
+	{
+		name:    "generate",
+		args:    "<directory_path> [flags]",
+		summary: "Scan and create the Word documents (requires UNIDOC_LICENSE_API_KEY).",
+		run:     runGenerate,
+	},
+	{
+		name:    "verify",
+		args:    "<directory_path> [flags]",
+		summary: "Check the configuration and scan result; exits non-zero if generation would fail.",
+		run:     runVerify,
+	},
+	{
+		name:    "config print",
+		args:    "[directory_path] [flags]",
+		summary: "Print the effective configuration (defaults + profile + config file + flags) as YAML.",
+		run:     runConfigPrint,
+	},
+}
+
 func main() {
-	if len(os.Args) < 2 {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
 		printUsage()
-		os.Exit(1)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
 	}
 
-	// ✅ Load .env file trước khi làm gì khác
-	if err := config.LoadEnv(); err != nil {
-		fmt.Printf("⚠️ Warning: %v\n", err)
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		if strings.HasPrefix(args[0], "-") || isCommandPrefix(args[0]) {
+			fmt.Printf("❌ Unknown command or flag: %s\n\n", args[0])
+			printUsage()
+			return 2
+		}
+		// ✅ Tương thích cú pháp cũ: `copyright-tool <dir> [--exclude=...]` = generate
+		cmd, rest = findCommandByName("generate"), args
 	}
 
-	rootDir := os.Args[1]
+	fs, flags := newFlagSet(cmd, os.Stderr)
+	positional, err := parseInterspersed(fs, rest)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	rootDir := "."
+	switch {
+	case len(positional) == 1:
+		rootDir = positional[0]
+	case len(positional) > 1:
+		fmt.Fprintf(os.Stderr, "❌ Unexpected arguments: %s\n", strings.Join(positional[1:], " "))
+		fs.Usage()
+		return 2
+	case cmd.name != "config print":
+		fmt.Fprintf(os.Stderr, "❌ Missing <directory_path>\n")
+		fs.Usage()
+		return 2
+	}
 
 	// Validate directory
-	if _, err := os.Stat(rootDir); os.IsNotExist(err) {
-		fmt.Printf("❌ Directory does not exist: %s\n", rootDir)
-		os.Exit(1)
+	if info, err := os.Stat(rootDir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "❌ Directory does not exist: %s\n", rootDir)
+		return 1
+	}
+
+	// Load configuration: mặc định → profile → copyright.yaml/.json → flags
+	cfg, err := config.LoadForProject(rootDir, flags.configPath, flags.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid configuration: %v\n", err)
+		return 1
+	}
+	flags.applyTo(cfg, fs)
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid configuration: %v\n", err)
+		return 1
+	}
+
+	if err := cmd.run(rootDir, cfg, flags); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// ✅ Tìm subcommand (hỗ trợ lệnh 2 từ như "config print")
+func findCommand(args []string) (*command, []string) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):]
+		}
+	}
+	return nil, args
+}
+
+func isCommandPrefix(word string) bool {
+	for _, cmd := range commands {
+		if strings.Fields(cmd.name)[0] == word {
+			return true
+		}
+	}
+	return false
+}
+
+func findCommandByName(name string) *command {
+	cmd, _ := findCommand(strings.Fields(name))
+	return cmd
+}
+
+func runScan(rootDir string, cfg *config.Config, flags *cliFlags) error {
+	fileProcessor := fileprocessor.New(cfg)
+
+	if flags.explain != "" {
+		decision, err := fileProcessor.Explain(rootDir, flags.explain)
+		if err != nil {
+			return fmt.Errorf("error explaining %s: %v", flags.explain, err)
+		}
+		if flags.format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(decision)
+		}
+		printDecision(decision)
+		return nil
+	}
+
+	switch flags.format {
+	case "table":
+	case "json":
+		// ✅ JSON ra stdout, log tiến trình chuyển sang stderr để pipeline đọc được
+		cfg.Output = os.Stderr
+		if _, err := fileProcessor.Scan(rootDir); err != nil {
+			return fmt.Errorf("error scanning directory: %v", err)
+		}
+		if err := saveReport(fileProcessor, flags); err != nil {
+			return err
+		}
+		return fileProcessor.WriteDecisionsJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown --format %q (expected table or json)", flags.format)
+	}
+
+	printHeader(rootDir, cfg)
+	files, err := fileProcessor.Scan(rootDir)
+	if err != nil {
+		return fmt.Errorf("error scanning directory: %v", err)
+	}
+	fileProcessor.PrintDecisionTable()
+	if len(files) == 0 {
+		fmt.Printf("⚠️  No files matched\n")
+	}
+	return saveReport(fileProcessor, flags)
+}
+
+// ✅ In quyết định cho một file (scan --explain)
+func printDecision(decision fileprocessor.Decision) {
+	status := "✅ included"
+	if !decision.Included {
+		status = "🚫 excluded"
 	}
+	fmt.Printf("%s: %s\n", decision.Path, status)
+	fmt.Printf("   reason: %s\n", decision.Reason)
+	if decision.Rule != "" {
+		fmt.Printf("   rule:   %s\n", decision.Rule)
+	}
+}
 
-	// Load configuration
-	cfg := config.LoadConfig()
+// ✅ Ghi --report-json nếu được yêu cầu
+func saveReport(fileProcessor *fileprocessor.FileProcessor, flags *cliFlags) error {
+	if flags.reportJSON == "" {
+		return nil
+	}
+	if err := fileProcessor.SaveDecisionsJSON(flags.reportJSON); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "📋 Decision report saved: %s\n", flags.reportJSON)
+	return nil
+}
 
-	// ✅ Xử lý arguments để thêm exclude files (nếu có)
-	if len(os.Args) > 2 {
-		handleAdditionalArgs(cfg, os.Args[2:])
+// ✅ Ghi lại --report-json kèm các đoạn đã chọn cho file rút gọn (nếu có)
+func saveExcerptReport(fileProcessor *fileprocessor.FileProcessor, docGenerator *generator.DocumentGenerator, files []models.CodeFile, flags *cliFlags) error {
+	report := docGenerator.ExcerptReport(files)
+	if report == nil {
+		return nil
+	}
+	fileProcessor.SetExcerptReport(report)
+	return saveReport(fileProcessor, flags)
+}
+
+// ✅ Che secret trong nội dung file trước khi đưa vào generator
+func redactFiles(cfg *config.Config, files []models.CodeFile) ([]models.CodeFile, error) {
+	fileRedactor, err := redactor.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return fileRedactor.Redact(files)
+}
+
+func runPreview(rootDir string, cfg *config.Config, flags *cliFlags) error {
+	printHeader(rootDir, cfg)
+	fileProcessor := fileprocessor.New(cfg)
+	files, err := fileProcessor.Scan(rootDir)
+	if err != nil {
+		return fmt.Errorf("error scanning directory: %v", err)
+	}
+	if err := saveReport(fileProcessor, flags); err != nil {
+		return err
+	}
+	if files, err = redactFiles(cfg, files); err != nil {
+		return err
+	}
+
+	docGenerator := generator.New(cfg)
+	docGenerator.SetSource(fileProcessor.Source())
+	if _, err := docGenerator.PrintPlan(files); err != nil {
+		return err
+	}
+	if err := saveExcerptReport(fileProcessor, docGenerator, files, flags); err != nil {
+		return err
+	}
+	docGenerator.PrintPageMap(files)
+	return nil
+}
+
+func runGenerate(rootDir string, cfg *config.Config, flags *cliFlags) error {
+	// ✅ Load .env file trước khi khởi tạo license
+	if err := config.LoadEnv(); err != nil {
+		fmt.Printf("⚠️ Warning: %v\n", err)
 	}
 
 	// Initialize components
@@ -43,64 +280,101 @@ func main() {
 
 	// Initialize license (sẽ tự động đọc từ .env)
 	if err := docGenerator.InitializeLicense(); err != nil {
-		fmt.Printf("❌ %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	printHeader(rootDir, cfg)
 
 	// Process files
-	files, err := fileProcessor.ScanDirectory(rootDir)
+	files, err := fileProcessor.Scan(rootDir)
 	if err != nil {
-		fmt.Printf("❌ Error scanning directory: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error scanning directory: %v", err)
+	}
+	if err := saveReport(fileProcessor, flags); err != nil {
+		return err
 	}
+	if files, err = redactFiles(cfg, files); err != nil {
+		return err
+	}
+	docGenerator.SetSource(fileProcessor.Source())
 
 	// Generate documents
 	if err := docGenerator.GenerateDocuments(files); err != nil {
-		fmt.Printf("❌ Error generating document: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error generating document: %v", err)
+	}
+	if err := saveExcerptReport(fileProcessor, docGenerator, files, flags); err != nil {
+		return err
 	}
 
-	printFooter()
+	printFooter(cfg)
+	return nil
 }
 
-// ✅ Xử lý arguments để thêm exclude files
-func handleAdditionalArgs(cfg *config.Config, args []string) {
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "--exclude=") {
-			filename := strings.TrimPrefix(arg, "--exclude=")
-			cfg.AddExcludeFile(filename)
-			fmt.Printf("🚫 Added to exclude list: %s\n", filename)
-		} else if strings.HasPrefix(arg, "--exclude-pattern=") {
-			pattern := strings.TrimPrefix(arg, "--exclude-pattern=")
-			cfg.AddExcludePattern(pattern)
-			fmt.Printf("🚫 Added exclude pattern: *%s*\n", pattern)
-		}
+func runVerify(rootDir string, cfg *config.Config, flags *cliFlags) error {
+	fileProcessor := fileprocessor.New(cfg)
+	files, err := fileProcessor.Scan(rootDir)
+	if err != nil {
+		return fmt.Errorf("error scanning directory: %v", err)
+	}
+	if err := saveReport(fileProcessor, flags); err != nil {
+		return err
+	}
+	if files, err = redactFiles(cfg, files); err != nil {
+		return fmt.Errorf("verification failed: %v", err)
+	}
+
+	docGenerator := generator.New(cfg)
+	docGenerator.SetSource(fileProcessor.Source())
+	if _, err := docGenerator.PrintPlan(files); err != nil {
+		return fmt.Errorf("verification failed: %v", err)
+	}
+	if err := saveExcerptReport(fileProcessor, docGenerator, files, flags); err != nil {
+		return err
 	}
+	fmt.Printf("✅ Verification passed (profile %s, %d files)\n", cfg.Profile, len(files))
+	return nil
+}
+
+func runConfigPrint(rootDir string, cfg *config.Config, flags *cliFlags) error {
+	data, err := cfg.ToYAML()
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+	if cfg.SourceFile != "" {
+		fmt.Printf("# Loaded from %s\n", cfg.SourceFile)
+	}
+	fmt.Print(string(data))
+	return nil
 }
 
 func printUsage() {
 	fmt.Println("📝 Go Code to Word - Optimized with File Exclusion (v2.1)")
 	fmt.Println("")
-	fmt.Println("Usage: go run main.go <directory_path> [options]")
-	fmt.Println("Example: go run main.go ./src")
+	fmt.Println("Usage: copyright-tool <command> <directory_path> [flags]")
+	fmt.Println("       copyright-tool <command> --help")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-14s %s\n", cmd.name, cmd.summary)
+	}
 	fmt.Println("")
-	fmt.Println("📂 Supported file types:")
-	fmt.Println("  ✅ .cs (C#)")
-	fmt.Println("  ✅ .dart (Dart)")
+	fmt.Println("  `copyright-tool <directory_path> [flags]` is kept as a shortcut for `generate`.")
 	fmt.Println("")
-	fmt.Println("🚫 File Exclusion Options:")
-	fmt.Println("  --exclude=filename          Exclude specific file (e.g., --exclude=program.cs)")
-	fmt.Println("  --exclude-pattern=pattern   Exclude files containing pattern")
+	fmt.Println("Examples:")
+	fmt.Println("  go run . preview ./src --profile=us-co-deposit")
+	fmt.Println("  go run . generate ./src --exclude=program.cs --exclude-pattern=secret --output-dir=out")
+	fmt.Println("  go run . scan ./src --explain=lib/generated/api.g.dart")
+	fmt.Println("  go run . config print ./src")
 	fmt.Println("")
-	fmt.Println("  Examples:")
-	fmt.Println("    go run main.go ./src --exclude=program.cs --exclude=database.cs")
-	fmt.Println("    go run main.go ./src --exclude-pattern=secret --exclude-pattern=config")
+	fmt.Println("📂 Supported file types (enabled by default: .cs, .dart):")
+	for _, lang := range config.BuiltinLanguages() {
+		fmt.Printf("  ✅ %s (%s)\n", lang.Name, strings.Join(lang.Extensions, ", "))
+	}
+	fmt.Println("  ➕ Enable with --extensions=.cs,.ts or `languages:` in copyright.yaml")
 	fmt.Println("")
 	fmt.Println("🚫 Default excluded files:")
 	fmt.Println("  📄 Exact files: program.cs, appsettings.json, database.cs, secrets.cs...")
-	fmt.Println("  🔍 Patterns: secret, password, apikey, config, setting, credential...")
+	fmt.Println("  🔍 Rules: *secret*, *password*, *apikey*, *config*, *setting*, *credential*, *.g.dart...")
 	fmt.Println("")
 	fmt.Println("🔑 Setup API Key (choose one):")
 	fmt.Println("  📄 Create .env file:")
@@ -115,20 +389,33 @@ func printUsage() {
 }
 
 func printHeader(rootDir string, cfg *config.Config) {
+	if cfg.Verbosity < config.VerbosityNormal {
+		return
+	}
 	fmt.Printf("🚀 Creating optimized Word document with file exclusion (v2.1)...\n")
 	fmt.Printf("📁 Source directory: %s\n", rootDir)
-	fmt.Printf("📝 Processing: .cs (C#) and .dart (Dart)\n")
-	fmt.Printf("📖 Optimization: %d lines/page, page break threshold: %d lines\n",
-		cfg.LinesPerPage, cfg.MinLinesForPageBreak)
+	if cfg.GitRef != "" {
+		fmt.Printf("🏷️  Git ref: %s (read from .git, working tree ignored)\n", cfg.GitRef)
+	}
+	if cfg.SourceFile != "" {
+		fmt.Printf("⚙️  Config file: %s\n", cfg.SourceFile)
+	}
+	fmt.Printf("📝 Processing: %s\n", cfg.Languages.Describe(cfg.SupportedExtensions))
+	fmt.Printf("🏷️  Profile: %s (%s, excerpt: %s)\n", cfg.Profile, cfg.PageSize, cfg.ExcerptStrategy)
+	fmt.Printf("📖 Optimization: %d lines/page, page break threshold: %d lines, wrap at %d chars (%dpt, line height %.2fpt, margins %gmm)\n",
+		cfg.RowsPerPage(), cfg.MinLinesForPageBreak, cfg.CodeWrapWidth(), cfg.CodeFontSize, cfg.CodeLineHeight(), cfg.MarginMM)
 	fmt.Printf("🚫 File exclusion: enabled (%d files, %d patterns)\n",
 		len(cfg.ExcludeFiles), len(cfg.ExcludePatterns))
 	fmt.Printf("💡 Features: Compact header + minimal separator + smart page break + sensitive file filtering\n")
 	fmt.Println(strings.Repeat("=", 70))
 }
 
-func printFooter() {
+func printFooter(cfg *config.Config) {
 	fmt.Println(strings.Repeat("=", 70))
-	fmt.Printf("✨ Completed! Check 'copyright_documents' directory\n")
+	fmt.Printf("✨ Completed! Check '%s' directory\n", cfg.OutputDir)
+	if cfg.Verbosity < config.VerbosityNormal {
+		return
+	}
 	fmt.Printf("💡 Word files have been optimized - saves 40-60%% paper!\n")
 	fmt.Printf("🎯 Smart page break and sensitive file filtering applied\n")
 	fmt.Printf("🔒 Sensitive files (config, secrets, etc.) were automatically excluded\n")
@@ -1,16 +1,46 @@
 package models
 
 type CodeFile struct {
-	FileName  string
+	FileName  string // Tên file (không có thư mục)
+	RelPath   string // Đường dẫn tương đối từ thư mục scan, phân tách bằng "/" (dùng để hiển thị và sắp xếp)
 	Extension string
+	Language  string // Tên hiển thị của ngôn ngữ (config.Language.Name)
+	Generated bool   // Có marker sinh tự động (generated_code.action = tag)
+	Encoding  string // Encoding gốc của file (utf-8, utf-8-bom, utf-16le, windows-1258...)
 	Lines     []string
 	Content   string
 	PageCount int
 }
 
+// PageRange là phần của một file nằm trên một trang (page_breaks = fixed).
 type PageRange struct {
+	Page      int // Trang trong document (từ 1)
 	FileIndex int
-	StartLine int
+	StartLine int // Dòng đầu / cuối (index trong Lines); StartLine > EndLine nếu trang chỉ có header
 	EndLine   int
-	Pages     int
+	Header    bool // Header của file nằm ở đầu phần này
+	Separator bool // Dòng phân cách sau EndLine
+}
+
+// ✅ Nguồn của lần scan (ghi vào metadata của file output)
+type SourceInfo struct {
+	RootDir string
+	GitRef  string // Ref được yêu cầu (tag, branch, hash) - rỗng nếu đọc từ ổ đĩa
+	Commit  string // Hash đầy đủ của commit đã đọc
+}
+
+// ✅ Nội dung đã chọn cho file rút gọn (ghi vào --report-json)
+type ExcerptReport struct {
+	Strategy    string        `json:"strategy"`
+	Boundaries  string        `json:"boundaries"`
+	TargetPages int           `json:"target_pages"`
+	Pages       int           `json:"pages"` // Số trang nội dung theo layout
+	Ranges      []SourceRange `json:"ranges"`
+}
+
+// SourceRange là khoảng dòng (từ 1) của một file được đưa vào file rút gọn.
+type SourceRange struct {
+	File      string `json:"file"`
+	FirstLine int    `json:"first_line"`
+	LastLine  int    `json:"last_line"`
 }
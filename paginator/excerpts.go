@@ -0,0 +1,412 @@
+// excerpts.go - Shortened document sections snapped to whole files or top-level declarations
+package paginator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/highlighter"
+	"copyright-code-word/models"
+	"sort"
+	"strings"
+)
+
+// ExcerptPart là một đoạn liên tiếp [StartLine, EndLine] của một file trong file rút gọn.
+type ExcerptPart struct {
+	FileIndex     int
+	StartLine     int
+	EndLine       int
+	Continued     bool // Bắt đầu giữa file: header "(continued) path, lines X–Y"
+	Separator     bool
+	OmittedBefore int // Số dòng bị lược ngay trước đoạn (banner "… N lines omitted …"), 0 = liền mạch
+}
+
+// ✅ Một điểm cắt chỉ được dùng nếu đoạn trích lấp được ít nhất tỷ lệ này của ngân sách,
+// không thì thử mức cắt mịn hơn (file → khai báo → dòng). Phần thiếu được dồn cho đoạn sau.
+const (
+	minExcerptFill = 0.5
+	// excerpt_boundaries = declarations: chỉ dừng ở cuối file khi gần như không bỏ phí trang
+	minFileFillForDeclarations = 0.9
+)
+
+// cutLevel là một mức điểm cắt: cuts[g] = được cắt ngay trước dòng toàn cục g.
+type cutLevel struct {
+	cuts    []bool
+	minFill float64
+}
+
+// excerptIndex đánh số các dòng của mọi file liên tiếp nhau (dòng toàn cục) để chọn khoảng trích.
+// Chiều cao (pt) tính như layoutCursor: header, hàng code, separator.
+type excerptIndex struct {
+	starts          []int // Dòng toàn cục đầu tiên của từng file, phần tử cuối = tổng số dòng
+	fileOf          []int // File của từng dòng toàn cục
+	heights         []float64
+	levels          []cutLevel // Theo thứ tự ưu tiên
+	headerHeight    float64
+	separatorHeight float64
+}
+
+func (p *Paginator) newExcerptIndex(files []models.CodeFile) *excerptIndex {
+	wrapWidth, tabWidth := p.config.CodeWrapWidth(), p.config.Indentation.TabWidth
+	syntax := highlighter.New(p.config)
+	lineHeight := p.config.CodeLineHeight()
+	x := &excerptIndex{headerHeight: p.headerHeight(), separatorHeight: p.config.SeparatorLineHeight()}
+
+	var fileCuts, declarationCuts []bool
+	for i, file := range files {
+		x.starts = append(x.starts, len(x.fileOf))
+		declarations := declarationStarts(file, syntax.Highlight(file), tabWidth)
+		for j, line := range file.Lines {
+			x.fileOf = append(x.fileOf, i)
+			x.heights = append(x.heights, float64(LineRows(line, wrapWidth, tabWidth))*lineHeight)
+			fileCuts = append(fileCuts, j == 0)
+			declarationCuts = append(declarationCuts, declarations[j])
+		}
+	}
+	x.starts = append(x.starts, len(x.fileOf))
+	fileCuts = append(fileCuts, true)
+	declarationCuts = append(declarationCuts, true)
+
+	x.levels = []cutLevel{{fileCuts, minFileFillForDeclarations}, {declarationCuts, minExcerptFill}}
+	if p.config.ExcerptBoundaries == config.ExcerptBoundaryFiles {
+		x.levels[0].minFill = minExcerptFill
+	}
+	return x
+}
+
+func (x *excerptIndex) total() int {
+	return len(x.fileOf)
+}
+
+func (x *excerptIndex) lastFile() int {
+	return len(x.starts) - 2
+}
+
+// fitForward trả về điểm kết thúc (không gồm) của đoạn bắt đầu tại start vừa budget (pt), và chiều cao đã dùng.
+func (x *excerptIndex) fitForward(start int, budget float64) (int, float64) {
+	bestLine, bestLineHeight := min(start+1, x.total()), 0.0
+	cuts, cutHeights := x.noCuts()
+
+	used := 0.0
+	for end := start + 1; end <= x.total(); end++ {
+		g := end - 1
+		file := x.fileOf[g]
+		if g == start || g == x.starts[file] {
+			used += x.headerHeight
+		}
+		used += x.heights[g]
+		height := used
+		if end == x.starts[file+1] && file < x.lastFile() {
+			height += x.separatorHeight
+		}
+		if height > budget && end > start+1 {
+			break
+		}
+
+		bestLine, bestLineHeight = end, height
+		for k, level := range x.levels {
+			if level.cuts[end] {
+				cuts[k], cutHeights[k] = end, height
+			}
+		}
+	}
+	return x.choose(cuts, cutHeights, bestLine, bestLineHeight, budget, budget)
+}
+
+// fitBackward trả về điểm bắt đầu (>= floor) của đoạn kết thúc tại end vừa budget (pt), và chiều cao đã dùng.
+// Đây là đoạn cuối nên điểm cắt bỏ phí quá maxSlack (pt) thì dùng mức cắt mịn hơn.
+func (x *excerptIndex) fitBackward(end, floor int, budget, maxSlack float64) (int, float64) {
+	bestLine, bestLineHeight := max(end-1, floor), 0.0
+	cuts, cutHeights := x.noCuts()
+
+	used := 0.0
+	for start := end - 1; start >= floor; start-- {
+		file := x.fileOf[start]
+		used += x.heights[start]
+		if start == end-1 || file != x.fileOf[start+1] {
+			used += x.headerHeight
+			if file < x.lastFile() && x.starts[file+1] <= end {
+				used += x.separatorHeight
+			}
+		}
+		if used > budget && start < end-1 {
+			break
+		}
+
+		bestLine, bestLineHeight = start, used
+		for k, level := range x.levels {
+			if level.cuts[start] {
+				cuts[k], cutHeights[k] = start, used
+			}
+		}
+	}
+	return x.choose(cuts, cutHeights, bestLine, bestLineHeight, budget, maxSlack)
+}
+
+func (x *excerptIndex) noCuts() ([]int, []float64) {
+	cuts := make([]int, len(x.levels))
+	for k := range cuts {
+		cuts[k] = -1
+	}
+	return cuts, make([]float64, len(x.levels))
+}
+
+// choose lấy điểm cắt của mức ưu tiên đầu tiên lấp đủ minFill ngân sách (và bỏ phí không quá maxSlack),
+// không có thì cắt theo dòng.
+func (x *excerptIndex) choose(cuts []int, cutHeights []float64, line int, lineHeight, budget, maxSlack float64) (int, float64) {
+	for k, cut := range cuts {
+		if cut >= 0 && cutHeights[k] >= x.levels[k].minFill*budget && budget-cutHeights[k] <= maxSlack {
+			return cut, cutHeights[k]
+		}
+	}
+	return line, lineHeight
+}
+
+// snapStart trả về điểm cắt gần target nhất trong [floor, ceil] theo thứ tự ưu tiên, không có thì chính target.
+func (x *excerptIndex) snapStart(target, floor, ceil int) int {
+	for _, level := range x.levels {
+		best := -1
+		for g := floor; g <= ceil; g++ {
+			if level.cuts[g] && (best < 0 || abs(g-target) < abs(best-target)) {
+				best = g
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+	}
+	return target
+}
+
+// lineAt trả về dòng toàn cục nằm ở vị trí offset (pt) tính từ đầu nội dung (đếm cả header).
+func (x *excerptIndex) lineAt(offset float64) int {
+	for g := range x.fileOf {
+		if g == x.starts[x.fileOf[g]] {
+			offset -= x.headerHeight
+		}
+		offset -= x.heights[g]
+		if offset < 0 {
+			return g
+		}
+	}
+	return max(0, x.total()-1)
+}
+
+// height trả về tổng chiều cao nội dung (header + code, không tính separator).
+func (x *excerptIndex) height() float64 {
+	total := float64(x.lastFile()+1) * x.headerHeight
+	for _, height := range x.heights {
+		total += height
+	}
+	return total
+}
+
+// SelectExcerptParts chọn các đoạn của file rút gọn (excerpt_boundaries = declarations / files) theo các cửa sổ
+// của ExcerptStrategy: cửa sổ đầu (Anchor 0) lấy từ đầu, cửa sổ cuối (Anchor 1) lấy đến hết, các cửa sổ khác
+// quanh vị trí Anchor. Điểm cắt ưu tiên ranh giới file rồi khai báo cấp cao nhất; phần dư của đoạn trước
+// được dồn cho đoạn sau để tổng vẫn đạt TargetPages.
+func (p *Paginator) SelectExcerptParts(files []models.CodeFile, windows []Window) []ExcerptPart {
+	x := p.newExcerptIndex(files)
+	if x.total() == 0 {
+		return nil
+	}
+	// Mỗi trang tính bớt một hàng code cho phần trống ở cuối trang khi đoạn không vừa bị đẩy sang trang sau
+	bannerHeight := p.config.CodeLineHeight()
+	pageHeight := p.config.UsableHeightPoints() - bannerHeight
+
+	var sections [][2]int
+	previousEnd, slack := 0, 0.0
+	for i, window := range windows {
+		if previousEnd >= x.total() {
+			break
+		}
+		budget := float64(window.Pages)*pageHeight + slack
+		if i > 0 {
+			budget -= bannerHeight
+		}
+
+		switch {
+		case i == 0 && window.Anchor == 0:
+			end, used := x.fitForward(0, budget)
+			sections = append(sections, [2]int{0, end})
+			previousEnd, slack = end, budget-used
+		case i == len(windows)-1 && window.Anchor == 1:
+			start, _ := x.fitBackward(x.total(), previousEnd, budget, p.config.UsableHeightPoints())
+			sections = append(sections, [2]int{start, x.total()})
+			previousEnd = x.total()
+		default:
+			target := max(previousEnd, x.lineAt(window.Anchor*(x.height()-budget)))
+			span := int(budget / p.config.CodeLineHeight() / 2)
+			start := x.snapStart(target, max(previousEnd, target-span), min(x.total()-1, target+span))
+
+			end, used := x.fitForward(start, budget)
+			sections = append(sections, [2]int{start, end})
+			previousEnd, slack = end, budget-used
+		}
+	}
+	return x.parts(sections)
+}
+
+// parts chia các đoạn (dòng toàn cục [start, end)) theo file; đoạn nối tiếp đúng chỗ đoạn trước dừng được gộp lại.
+func (x *excerptIndex) parts(sections [][2]int) []ExcerptPart {
+	var result []ExcerptPart
+	previousEnd := 0
+	for _, section := range sections {
+		start, end := max(section[0], previousEnd), section[1]
+		omitted := start - previousEnd
+		for g := start; g < end; {
+			file := x.fileOf[g]
+			fileStart, fileEnd := x.starts[file], x.starts[file+1]
+			partEnd := min(end, fileEnd)
+			separator := partEnd == fileEnd && file < x.lastFile()
+
+			if n := len(result); n > 0 && omitted == 0 && result[n-1].FileIndex == file {
+				result[n-1].EndLine = partEnd - fileStart - 1
+				result[n-1].Separator = separator
+			} else {
+				result = append(result, ExcerptPart{
+					FileIndex:     file,
+					StartLine:     g - fileStart,
+					EndLine:       partEnd - fileStart - 1,
+					Continued:     g > fileStart,
+					Separator:     separator,
+					OmittedBefore: omitted,
+				})
+			}
+			omitted = 0
+			g = partEnd
+		}
+		previousEnd = max(previousEnd, end)
+	}
+	return result
+}
+
+// PlaceParts tính bố cục file rút gọn dựng từ ExcerptPart (banner lược bỏ cao một hàng code).
+func (p *Paginator) PlaceParts(files []models.CodeFile, parts []ExcerptPart) Layout {
+	var placements []FilePlacement
+	placed := make(map[int]bool)
+	cursor := p.newCursor()
+	wrapWidth, tabWidth := p.config.CodeWrapWidth(), p.config.Indentation.TabWidth
+	lineHeight := p.config.CodeLineHeight()
+
+	for _, part := range parts {
+		if part.OmittedBefore > 0 {
+			cursor.add(lineHeight)
+		}
+		p.addHeader(cursor)
+		if !placed[part.FileIndex] {
+			placed[part.FileIndex] = true
+			placements = append(placements, FilePlacement{FileIndex: part.FileIndex, StartPage: cursor.page})
+		}
+		cursor.beginPart(part.FileIndex, part.StartLine, true)
+
+		file := files[part.FileIndex]
+		for line := part.StartLine; line <= part.EndLine; line++ {
+			cursor.addLine(line, LineRows(file.Lines[line], wrapWidth, tabWidth), lineHeight)
+		}
+		if part.Separator {
+			cursor.addSeparator(p.config.SeparatorLineHeight())
+		}
+	}
+	return Layout{Placements: placements, Pages: cursor.ranges, TotalPages: cursor.page}
+}
+
+// declarationStarts đánh dấu các dòng có thể cắt trước (khai báo cấp cao nhất), dòng đầu file luôn được cắt.
+// Heuristic: dòng không trống ngay sau dòng trống, ở độ sâu ngoặc nhọn ≤ độ sâu khai báo của file
+// (độ sâu nhỏ nhất mở từ hai block trở lên, ví dụ các method trong class); file không dùng ngoặc nhọn
+// (Python...) thì xét độ thụt lề ≤ mức thụt lề nhỏ thứ hai.
+func declarationStarts(file models.CodeFile, tokens [][]highlighter.Token, tabWidth int) []bool {
+	starts := make([]bool, len(file.Lines))
+	if len(starts) == 0 {
+		return starts
+	}
+	starts[0] = true
+
+	depths := make([]int, len(file.Lines))
+	opened := make(map[int]int)
+	usesBraces, firstBrace := false, -1
+	depth := 0
+	for i := range file.Lines {
+		depths[i] = depth
+		for _, token := range tokens[i] {
+			if token.Kind != highlighter.Plain {
+				continue
+			}
+			for _, r := range token.Text {
+				switch r {
+				case '{':
+					if !usesBraces {
+						usesBraces, firstBrace = true, i
+					}
+					opened[depth]++
+					depth++
+				case '}':
+					depth = max(0, depth-1)
+				}
+			}
+		}
+	}
+
+	var allowed func(i int) bool
+	if usesBraces {
+		declarationDepth := 0
+		for d := 0; opened[d] > 0; d++ {
+			if opened[d] >= 2 {
+				declarationDepth = d
+				break
+			}
+		}
+		allowed = func(i int) bool { return depths[i] <= declarationDepth }
+	} else {
+		indents := distinctIndents(file.Lines, tabWidth)
+		limit := indents[min(1, len(indents)-1)]
+		allowed = func(i int) bool { return indentWidth(file.Lines[i], tabWidth) <= limit }
+	}
+
+	for i := 1; i < len(file.Lines); i++ {
+		// Không tách phần using / import đầu file khỏi khai báo đầu tiên
+		if usesBraces && i <= firstBrace {
+			continue
+		}
+		if strings.TrimSpace(file.Lines[i-1]) == "" && strings.TrimSpace(file.Lines[i]) != "" && allowed(i) {
+			starts[i] = true
+		}
+	}
+	return starts
+}
+
+func distinctIndents(lines []string, tabWidth int) []int {
+	seen := make(map[int]bool)
+	indents := []int{0}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if width := indentWidth(line, tabWidth); !seen[width] && width > 0 {
+			seen[width] = true
+			indents = append(indents, width)
+		}
+	}
+	sort.Ints(indents)
+	return indents
+}
+
+func indentWidth(line string, tabWidth int) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += max(1, tabWidth)
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
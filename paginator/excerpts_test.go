@@ -0,0 +1,315 @@
+package paginator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/highlighter"
+	"copyright-code-word/models"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func codeFile(relPath, content string) models.CodeFile {
+	name := relPath[strings.LastIndex(relPath, "/")+1:]
+	return models.CodeFile{
+		FileName:  name,
+		RelPath:   relPath,
+		Extension: name[strings.LastIndex(name, "."):],
+		Lines:     strings.Split(content, "\n"),
+	}
+}
+
+func numberedFile(relPath string, lines int) models.CodeFile {
+	numbered := make([]string, lines)
+	for i := range numbered {
+		numbered[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return codeFile(relPath, strings.Join(numbered, "\n"))
+}
+
+// csharpFile tạo class có methods method, mỗi method body dòng, các method cách nhau một dòng trống.
+func csharpFile(index, methods, body int) models.CodeFile {
+	var b strings.Builder
+	fmt.Fprintf(&b, "using System;\n\nnamespace App\n{\n    public class Svc%02d\n    {\n", index)
+	for m := 0; m < methods; m++ {
+		if m > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "        public int M%d()\n        {\n", m)
+		for l := 0; l < body; l++ {
+			fmt.Fprintf(&b, "            Run(%d);\n", l)
+		}
+		b.WriteString("            return 0;\n        }\n")
+	}
+	b.WriteString("    }\n}")
+	return codeFile(fmt.Sprintf("Services/Svc%02d.cs", index), b.String())
+}
+
+// pythonFile tạo module có functions hàm cấp cao nhất, cách nhau hai dòng trống.
+func pythonFile(index, functions, body int) models.CodeFile {
+	var b strings.Builder
+	b.WriteString("import os\n")
+	for f := 0; f < functions; f++ {
+		fmt.Fprintf(&b, "\n\ndef task_%d(path):\n", f)
+		for l := 0; l < body; l++ {
+			fmt.Fprintf(&b, "    os.stat(path)  # %d\n", l)
+		}
+		b.WriteString("    return path")
+	}
+	return codeFile(fmt.Sprintf("tasks/task_%02d.py", index), b.String())
+}
+
+func TestDeclarationStarts(t *testing.T) {
+	tests := []struct {
+		name string
+		file models.CodeFile
+		want []int
+	}{
+		{
+			name: "braces: methods inside class inside namespace",
+			file: codeFile("A.cs", strings.Join([]string{
+				"using System;", // 0
+				"",
+				"namespace App", // 2: trước ngoặc đầu tiên, không tách khỏi using
+				"{",
+				"    public class A",
+				"    {",
+				"        public void M1()",
+				"        {",
+				"            Run();",
+				"",
+				"            Run();", // 10: trong thân method
+				"        }",
+				"",
+				"        public void M2()", // 13
+				"        {",
+				"            var s = \"{ {\";", // ngoặc trong chuỗi không tính
+				"        }",
+				"    }",
+				"}",
+			}, "\n")),
+			want: []int{0, 13},
+		},
+		{
+			name: "braces: top-level functions",
+			file: codeFile("a.go", strings.Join([]string{
+				"package a",
+				"",
+				"func A() {", // 2: ngoặc đầu tiên
+				"\treturn",
+				"}",
+				"",
+				"func B() {", // 6
+				"\tif x {",
+				"",
+				"\t\treturn", // 9: trong thân hàm
+				"\t}",
+				"}",
+			}, "\n")),
+			want: []int{0, 6},
+		},
+		{
+			name: "python: indentation",
+			file: codeFile("a.py", strings.Join([]string{
+				"import os",
+				"",
+				"",
+				"def f0():", // 3
+				"    x = 0",
+				"    return x",
+				"",
+				"",
+				"class A:", // 8
+				"    def m(self):",
+				"        pass",
+				"",
+				"    def n(self):", // 12: method, thụt lề mức thứ hai
+				"        if x:",
+				"",
+				"            pass", // 15: sâu hơn mức khai báo
+			}, "\n")),
+			want: []int{0, 3, 8, 12},
+		},
+	}
+
+	cfg := config.LoadConfig()
+	syntax := highlighter.New(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts := declarationStarts(tt.file, syntax.Highlight(tt.file), cfg.Indentation.TabWidth)
+			var got []int
+			for i, start := range starts {
+				if start {
+					got = append(got, i)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("declarationStarts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartsFromSpans(t *testing.T) {
+	files := []models.CodeFile{numberedFile("a.cs", 14), numberedFile("b.cs", 9), numberedFile("c.cs", 17)}
+	tests := []struct {
+		name  string
+		spans map[int][]LineRange
+		want  []ExcerptPart
+	}{
+		{
+			name:  "whole files are contiguous",
+			spans: map[int][]LineRange{0: {{0, 13}}, 1: {{0, 8}}},
+			want: []ExcerptPart{
+				{FileIndex: 0, StartLine: 0, EndLine: 13, Separator: true},
+				{FileIndex: 1, StartLine: 0, EndLine: 8, Separator: true},
+			},
+		},
+		{
+			name:  "overlapping and adjacent spans merge",
+			spans: map[int][]LineRange{0: {{4, 6}, {0, 3}, {5, 9}}},
+			want:  []ExcerptPart{{FileIndex: 0, StartLine: 0, EndLine: 9}},
+		},
+		{
+			name:  "mid-file start is continued, omitted lines span files",
+			spans: map[int][]LineRange{0: {{0, 3}, {8, 9}}, 2: {{10, 16}}},
+			want: []ExcerptPart{
+				{FileIndex: 0, StartLine: 0, EndLine: 3},
+				{FileIndex: 0, StartLine: 8, EndLine: 9, Continued: true, OmittedBefore: 4},
+				// 4 dòng cuối file 0 + 9 dòng file 1 + 10 dòng đầu file 2
+				{FileIndex: 2, StartLine: 10, EndLine: 16, Continued: true, OmittedBefore: 23},
+			},
+		},
+		{
+			name:  "last file has no separator",
+			spans: map[int][]LineRange{2: {{0, 16}}},
+			want:  []ExcerptPart{{FileIndex: 2, StartLine: 0, EndLine: 16, OmittedBefore: 23}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partsFromSpans(files, tt.spans)
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", tt.want) {
+				t.Errorf("partsFromSpans =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseCut(t *testing.T) {
+	// Mức 0 = ranh giới file (cần lấp 90%), mức 1 = khai báo (cần lấp 50%)
+	x := &excerptIndex{levels: []cutLevel{{minFill: minFileFillForDeclarations}, {minFill: minExcerptFill}}}
+	tests := []struct {
+		name       string
+		cuts       []int
+		cutHeights []float64
+		maxSlack   float64
+		want       int
+	}{
+		{"file boundary fills the budget", []int{40, 45}, []float64{95, 98}, 100, 40},
+		{"file boundary too short, declaration", []int{20, 45}, []float64{60, 98}, 100, 45},
+		{"declaration too short, line", []int{20, 30}, []float64{30, 40}, 100, 49},
+		{"no cuts, line", []int{-1, -1}, []float64{0, 0}, 100, 49},
+		{"slack over the limit skips the file boundary", []int{40, 45}, []float64{95, 98}, 3, 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := x.choose(tt.cuts, tt.cutHeights, 49, 99, 100, tt.maxSlack)
+			if got != tt.want {
+				t.Errorf("choose = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectExcerptParts(t *testing.T) {
+	var csharp, python []models.CodeFile
+	for i := 0; i < 20; i++ {
+		csharp = append(csharp, csharpFile(i, 3+i%5, 8+i%7*3))
+		python = append(python, pythonFile(i, 4+i%3, 10+i%4*5))
+	}
+
+	tests := []struct {
+		name          string
+		files         []models.CodeFile
+		strategy      string
+		boundaries    string
+		targetPages   int
+		wantContinued bool // Có đoạn bắt đầu giữa file (header "(continued)")
+	}{
+		{"csharp first-middle-last declarations", csharp, config.ExcerptFirstMiddleLast, config.ExcerptBoundaryDeclarations, 4, true},
+		{"csharp first-last declarations", csharp, config.ExcerptFirstLast, config.ExcerptBoundaryDeclarations, 4, true},
+		{"csharp evenly-spaced declarations", csharp, config.ExcerptEvenlySpaced, config.ExcerptBoundaryDeclarations, 6, true},
+		// Đoạn cuối không lấp được bằng nguyên file thì bắt đầu ở khai báo
+		{"csharp first-middle-last files", csharp, config.ExcerptFirstMiddleLast, config.ExcerptBoundaryFiles, 10, true},
+		{"python first-middle-last declarations", python, config.ExcerptFirstMiddleLast, config.ExcerptBoundaryDeclarations, 4, true},
+		{"python first-last declarations", python, config.ExcerptFirstLast, config.ExcerptBoundaryDeclarations, 6, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.LoadConfig()
+			cfg.TargetPages, cfg.ExcerptStrategy, cfg.ExcerptBoundaries = tt.targetPages, tt.strategy, tt.boundaries
+			cfg.ExcerptSections = 3
+			p := New(cfg)
+			selection, err := NewExcerptStrategy(cfg).Select(tt.files)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parts := p.SelectExcerptParts(tt.files, selection.Windows)
+			if pages := p.PlaceParts(tt.files, parts).TotalPages; pages != tt.targetPages {
+				t.Errorf("shortened document has %d pages, want target_pages %d", pages, tt.targetPages)
+			}
+			checkParts(t, cfg, tt.files, parts, tt.wantContinued)
+		})
+	}
+}
+
+// checkParts kiểm tra số dòng bị lược, cờ Continued / Separator và điểm bắt đầu giữa file nằm ở khai báo.
+func checkParts(t *testing.T, cfg *config.Config, files []models.CodeFile, parts []ExcerptPart, wantContinued bool) {
+	t.Helper()
+	if len(parts) == 0 {
+		t.Fatal("no parts selected")
+	}
+	starts := []int{0}
+	for _, file := range files {
+		starts = append(starts, starts[len(starts)-1]+len(file.Lines))
+	}
+	syntax := highlighter.New(cfg)
+
+	previousEnd, continued := 0, false
+	for _, part := range parts {
+		file := files[part.FileIndex]
+		start := starts[part.FileIndex] + part.StartLine
+		if part.OmittedBefore != start-previousEnd {
+			t.Errorf("%+v: OmittedBefore = %d, want %d", part, part.OmittedBefore, start-previousEnd)
+		}
+		if part.Continued != (part.StartLine > 0) {
+			t.Errorf("%+v: Continued does not match the start line", part)
+		}
+		wantSeparator := part.EndLine == len(file.Lines)-1 && part.FileIndex < len(files)-1
+		if part.Separator != wantSeparator {
+			t.Errorf("%+v: Separator = %v, want %v", part, part.Separator, wantSeparator)
+		}
+		if part.Continued {
+			continued = true
+			if !declarationStarts(file, syntax.Highlight(file), cfg.Indentation.TabWidth)[part.StartLine] {
+				t.Errorf("%+v: %s line %d (%q) is not a declaration start",
+					part, file.RelPath, part.StartLine+1, file.Lines[part.StartLine])
+			}
+		}
+		previousEnd = starts[part.FileIndex] + part.EndLine + 1
+	}
+	if parts[0].FileIndex != 0 || parts[0].StartLine != 0 {
+		t.Errorf("first part %+v does not start at the beginning", parts[0])
+	}
+	if last := parts[len(parts)-1]; last.FileIndex != len(files)-1 || last.EndLine != len(files[last.FileIndex].Lines)-1 {
+		t.Errorf("last part %+v does not reach the end", last)
+	}
+	if continued != wantContinued {
+		t.Errorf("continued parts = %v, want %v", continued, wantContinued)
+	}
+}
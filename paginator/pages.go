@@ -0,0 +1,129 @@
+// pages.go - Fixed page model: which part of which file is on each page (page_breaks = fixed)
+package paginator
+
+import "copyright-code-word/models"
+
+// PageSpan là một khoảng trang [First, Last] của file đầy đủ được chọn vào file rút gọn.
+type PageSpan struct {
+	First int
+	Last  int
+}
+
+// CalculatePageRanges chia file Word đầy đủ thành các trang, mỗi trang tối đa RowsPerPage hàng
+// (header, hàng code đã wrap, separator). Generator ngắt trang cứng giữa các trang nên đây là nguồn duy nhất
+// cho số trang, trang bắt đầu của file và trang được trích vào file rút gọn.
+func (p *Paginator) CalculatePageRanges(files []models.CodeFile) []models.PageRange {
+	var ranges []models.PageRange
+	capacity := p.config.RowsPerPage()
+	wrapWidth, tabWidth := p.config.CodeWrapWidth(), p.config.Indentation.TabWidth
+	page, used := 1, 0
+
+	nextPage := func() {
+		if used > 0 {
+			page++
+			used = 0
+		}
+	}
+
+	for i, file := range files {
+		rows := FileRows(file, wrapWidth, tabWidth)
+		firstLineRows := 0
+		if len(file.Lines) > 0 {
+			firstLineRows = LineRows(file.Lines[0], wrapWidth, tabWidth)
+		}
+
+		// Smart page break, và không để header đứng một mình cuối trang
+		if (used > p.config.MinLinesForPageBreak && used+p.config.CompactHeaderLines+rows > capacity) ||
+			used+p.config.CompactHeaderLines+firstLineRows > capacity {
+			nextPage()
+		}
+
+		current := models.PageRange{Page: page, FileIndex: i, StartLine: 0, EndLine: -1, Header: true}
+		used += p.config.CompactHeaderLines
+
+		for lineIndex, line := range file.Lines {
+			lineRows := LineRows(line, wrapWidth, tabWidth)
+			if used > 0 && used+lineRows > capacity {
+				ranges = append(ranges, current)
+				nextPage()
+				current = models.PageRange{Page: page, FileIndex: i, StartLine: lineIndex, EndLine: lineIndex - 1}
+			}
+			current.EndLine = lineIndex
+			used += lineRows
+
+			// Dòng dài hơn một trang: Word tự tràn sang các trang sau, bộ đếm đi theo
+			if used > capacity {
+				page += (used - 1) / capacity
+				used = (used-1)%capacity + 1
+			}
+		}
+
+		// Separator không vừa thì bỏ (file sau bắt đầu ở trang mới)
+		if i < len(files)-1 && used+p.config.FileSeparatorLines <= capacity {
+			current.Separator = true
+			used += p.config.FileSeparatorLines
+		}
+		ranges = append(ranges, current)
+	}
+	return ranges
+}
+
+// LastPage trả về số trang của danh sách PageRange.
+func LastPage(ranges []models.PageRange) int {
+	if len(ranges) == 0 {
+		return 0
+	}
+	return ranges[len(ranges)-1].Page
+}
+
+// PlacementsFromRanges trả về trang bắt đầu của các file có header trong ranges.
+func PlacementsFromRanges(ranges []models.PageRange) []FilePlacement {
+	var placements []FilePlacement
+	for _, r := range ranges {
+		if r.Header {
+			placements = append(placements, FilePlacement{FileIndex: r.FileIndex, StartPage: r.Page})
+		}
+	}
+	return placements
+}
+
+// SelectPageSpans đổi các cửa sổ trang sang khoảng trang của file đầy đủ (page_breaks = fixed),
+// cửa sổ sau không chồng lên cửa sổ trước.
+func (p *Paginator) SelectPageSpans(totalPages int, windows []Window) []PageSpan {
+	if totalPages <= p.config.TargetPages {
+		return []PageSpan{{First: 1, Last: totalPages}}
+	}
+
+	var spans []PageSpan
+	previousLast := 0
+	for _, window := range windows {
+		first := max(previousLast+1, 1+int(window.Anchor*float64(totalPages-window.Pages)+0.5))
+		last := min(totalPages, first+window.Pages-1)
+		if first > last {
+			continue
+		}
+		spans = append(spans, PageSpan{First: first, Last: last})
+		previousLast = last
+	}
+	return spans
+}
+
+// SelectPages trả về các PageRange thuộc spans, đánh lại số trang liên tiếp từ 1.
+func SelectPages(ranges []models.PageRange, spans []PageSpan) []models.PageRange {
+	var selected []models.PageRange
+	newPage, lastPage := 0, 0
+	for _, span := range spans {
+		for _, r := range ranges {
+			if r.Page < span.First || r.Page > span.Last {
+				continue
+			}
+			if r.Page != lastPage {
+				newPage++
+				lastPage = r.Page
+			}
+			r.Page = newPage
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
@@ -13,6 +13,8 @@ func New(cfg *config.Config) *Paginator {
 	return &Paginator{config: cfg}
 }
 
+// CalculateTotalPages ước tính số trang theo lines_per_page (mỗi header / separator tính là một số dòng cố định).
+// Số trang theo layout thực tế: LayoutPages.
 func (p *Paginator) CalculateTotalPages(files []models.CodeFile) int {
 	totalLines := 0
 
@@ -20,8 +22,8 @@ func (p *Paginator) CalculateTotalPages(files []models.CodeFile) int {
 		// Header compact
 		totalLines += p.config.CompactHeaderLines
 
-		// File content
-		totalLines += len(file.Lines)
+		// File content (dòng dài tính thêm hàng tiếp nối)
+		totalLines += FileRows(file, p.config.CodeWrapWidth(), p.config.Indentation.TabWidth)
 
 		// Separator (except last file)
 		if i < len(files)-1 {
@@ -32,63 +34,59 @@ func (p *Paginator) CalculateTotalPages(files []models.CodeFile) int {
 	return (totalLines + p.config.LinesPerPage - 1) / p.config.LinesPerPage
 }
 
-func (p *Paginator) CalculatePageRanges(files []models.CodeFile) []models.PageRange {
-	pageRanges := make([]models.PageRange, 0)
-	currentPageLines := 0
-
-	for fileIndex, file := range files {
-		linesRemaining := len(file.Lines)
-		startLine := 0
-
-		for linesRemaining > 0 {
-			linesToAdd := min(p.config.LinesPerPage-currentPageLines, linesRemaining)
-
-			if currentPageLines > 0 &&
-				linesToAdd < p.config.MinLinesForPageBreak &&
-				linesRemaining > p.config.MinLinesForPageBreak {
-				currentPageLines = 0
-				linesToAdd = min(p.config.LinesPerPage, linesRemaining)
-			}
-
-			endLine := startLine + linesToAdd - 1
-			if endLine >= len(file.Lines) {
-				endLine = len(file.Lines) - 1
-			}
-
-			pageRanges = append(pageRanges, models.PageRange{
-				FileIndex: fileIndex,
-				StartLine: startLine,
-				EndLine:   endLine,
-				Pages:     1,
-			})
-
-			startLine = endLine + 1
-			linesRemaining = len(file.Lines) - startLine
-			currentPageLines = (currentPageLines + linesToAdd) % p.config.LinesPerPage
-		}
+// SelectLineRanges đổi các cửa sổ trang sang khoảng hàng toàn cục (excerpt_boundaries = lines):
+// mỗi cửa sổ dài Pages × số hàng mỗi trang, đặt ở vị trí Anchor và không chồng lên cửa sổ trước.
+// Header của file cắt giữa chừng và khoảng trống khi sang trang làm file rút gọn dài hơn ước tính theo hàng,
+// nên số hàng mỗi trang được giảm dần đến khi bố cục thực tế (PlaceParts của LineRangeParts) vừa TargetPages.
+func (p *Paginator) SelectLineRanges(files []models.CodeFile, windows []Window) []LineRange {
+	totalLines := p.calculateTotalContentLines(files)
+	ranges := lineWindows(totalLines, windows, p.config.RowsPerPage())
+	if p.lineRangePages(files, ranges) <= p.config.TargetPages {
+		return ranges
 	}
 
-	return pageRanges
+	// Tìm nhị phân số hàng mỗi trang lớn nhất còn vừa
+	low, high := 1, p.config.RowsPerPage()-1
+	best := lineWindows(totalLines, windows, low)
+	for low <= high {
+		rowsPerPage := (low + high) / 2
+		candidate := lineWindows(totalLines, windows, rowsPerPage)
+		if p.lineRangePages(files, candidate) <= p.config.TargetPages {
+			best, low = candidate, rowsPerPage+1
+		} else {
+			high = rowsPerPage - 1
+		}
+	}
+	return best
 }
 
-func (p *Paginator) CalculateContentSections(files []models.CodeFile) (firstSection, middleStart, middleEnd, lastStart, totalLines int) {
-	totalLines = p.calculateTotalContentLines(files)
-	linesPerSection := (p.config.TargetPages * p.config.LinesPerPage) / 3
-
-	firstSection = min(linesPerSection, totalLines)
-	middleStart = max(0, (totalLines/2)-(linesPerSection/2))
-	middleEnd = min(totalLines, middleStart+linesPerSection)
-	lastStart = max(0, totalLines-linesPerSection)
+func (p *Paginator) lineRangePages(files []models.CodeFile, ranges []LineRange) int {
+	return p.PlaceParts(files, p.LineRangeParts(files, ranges)).TotalPages
+}
 
-	return
+func lineWindows(totalLines int, windows []Window, rowsPerPage int) []LineRange {
+	var ranges []LineRange
+	previousEnd := 0
+	for _, window := range windows {
+		size := window.Pages * rowsPerPage
+		start := max(previousEnd, int(window.Anchor*float64(max(0, totalLines-size))+0.5))
+		end := min(totalLines, start+size)
+		if start >= end {
+			continue
+		}
+		ranges = append(ranges, LineRange{Start: start, End: end - 1})
+		previousEnd = end
+	}
+	return ranges
 }
 
+// calculateTotalContentLines đếm theo hàng hiển thị (header + hàng code đã wrap + separator).
 func (p *Paginator) calculateTotalContentLines(files []models.CodeFile) int {
 	totalLines := 0
 
 	for i, file := range files {
 		totalLines += p.config.CompactHeaderLines
-		totalLines += len(file.Lines)
+		totalLines += FileRows(file, p.config.CodeWrapWidth(), p.config.Indentation.TabWidth)
 
 		if i < len(files)-1 {
 			totalLines += p.config.FileSeparatorLines
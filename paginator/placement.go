@@ -0,0 +1,178 @@
+// placement.go - Where each file starts in the generated document, simulated with the real layout heights
+package paginator
+
+import "copyright-code-word/models"
+
+// LineRange là một khoảng hàng toàn cục [Start, End] của file rút gọn.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Lines trả về số hàng của khoảng.
+func (r LineRange) Lines() int {
+	return r.End - r.Start + 1
+}
+
+// FilePlacement là vị trí header của một file trong document (trang code đầu tiên = 1).
+type FilePlacement struct {
+	FileIndex   int
+	BreakBefore bool // Smart page break ngay trước header (chỉ có ở file đầy đủ)
+	StartPage   int
+}
+
+// Layout là bố cục một document: trang bắt đầu của file, bản đồ trang (trang → file, dòng) và số trang code.
+type Layout struct {
+	Placements []FilePlacement
+	Pages      []models.PageRange
+	TotalPages int
+}
+
+// ✅ layoutCursor mô phỏng Word xếp các đoạn có chiều cao exact (pt) lên trang,
+// đồng thời ghi lại phần file / dòng nằm trên từng trang
+type layoutCursor struct {
+	usable float64
+	y      float64
+	page   int
+	ranges []models.PageRange
+}
+
+func (p *Paginator) newCursor() *layoutCursor {
+	return &layoutCursor{usable: p.config.UsableHeightPoints(), page: 1}
+}
+
+// add đặt một đoạn cao height; không vừa phần còn lại thì sang trang mới.
+func (lc *layoutCursor) add(height float64) {
+	if lc.y > 0 && lc.y+height > lc.usable+0.01 {
+		lc.page++
+		lc.y = 0
+	}
+	lc.y += height
+}
+
+// beginPart mở phần mới của file fileIndex từ dòng startLine (header = header file đứng đầu phần).
+func (lc *layoutCursor) beginPart(fileIndex, startLine int, header bool) {
+	lc.ranges = append(lc.ranges, models.PageRange{
+		Page: lc.page, FileIndex: fileIndex, StartLine: startLine, EndLine: startLine - 1, Header: header,
+	})
+}
+
+// addLine đặt các hàng của dòng lineIndex; dòng bị cắt sang trang mới thì có mặt ở cả hai trang.
+func (lc *layoutCursor) addLine(lineIndex, rows int, height float64) {
+	for i := 0; i < rows; i++ {
+		lc.add(height)
+		if lc.ranges[len(lc.ranges)-1].Page != lc.page {
+			lc.beginPart(lc.ranges[len(lc.ranges)-1].FileIndex, lineIndex, false)
+		}
+		lc.ranges[len(lc.ranges)-1].EndLine = lineIndex
+	}
+}
+
+func (lc *layoutCursor) addSeparator(height float64) {
+	lc.add(height)
+	current := lc.ranges[len(lc.ranges)-1]
+	if current.Page != lc.page {
+		lc.beginPart(current.FileIndex, current.EndLine+1, false)
+	}
+	lc.ranges[len(lc.ranges)-1].Separator = true
+}
+
+func (lc *layoutCursor) breakPage() {
+	if lc.y > 0 {
+		lc.page++
+		lc.y = 0
+	}
+}
+
+// headerHeight là chiều cao header file: dòng tên file + một đoạn trống cao bằng hàng code.
+func (p *Paginator) headerHeight() float64 {
+	return p.config.HeaderLineHeight() + p.config.CodeLineHeight()
+}
+
+func (p *Paginator) addHeader(lc *layoutCursor) {
+	lc.add(p.config.HeaderLineHeight())
+	lc.add(p.config.CodeLineHeight())
+}
+
+// FullLayout trả về bố cục file Word đầy đủ: page_breaks = fixed theo CalculatePageRanges,
+// flow theo mô phỏng layout (placeFiles).
+func (p *Paginator) FullLayout(files []models.CodeFile) Layout {
+	if p.config.FixedLayout() {
+		return LayoutFromRanges(p.CalculatePageRanges(files))
+	}
+	return p.placeFiles(files)
+}
+
+// LayoutFromRanges dựng Layout từ các PageRange đã tính (page_breaks = fixed).
+func LayoutFromRanges(ranges []models.PageRange) Layout {
+	return Layout{Placements: PlacementsFromRanges(ranges), Pages: ranges, TotalPages: LastPage(ranges)}
+}
+
+// placeFiles tính smart page break, trang bắt đầu của từng file và bản đồ trang của file Word đầy đủ.
+func (p *Paginator) placeFiles(files []models.CodeFile) Layout {
+	placements := make([]FilePlacement, 0, len(files))
+	cursor := p.newCursor()
+	wrapWidth, tabWidth := p.config.CodeWrapWidth(), p.config.Indentation.TabWidth
+	lineHeight := p.config.CodeLineHeight()
+	breakThreshold := float64(p.config.MinLinesForPageBreak) * lineHeight
+
+	for i, file := range files {
+		rows := FileRows(file, wrapWidth, tabWidth)
+		fileHeight := p.headerHeight() + float64(rows)*lineHeight
+		if i < len(files)-1 {
+			fileHeight += p.config.SeparatorLineHeight()
+		}
+
+		placement := FilePlacement{FileIndex: i}
+		// Smart page break: trang đã đầy quá ngưỡng và file không vừa phần còn lại
+		if cursor.y > breakThreshold && cursor.y+fileHeight > cursor.usable {
+			placement.BreakBefore = true
+			cursor.breakPage()
+		}
+
+		p.addHeader(cursor)
+		placement.StartPage = cursor.page
+		placements = append(placements, placement)
+		cursor.beginPart(i, 0, true)
+
+		for lineIndex, line := range file.Lines {
+			cursor.addLine(lineIndex, LineRows(line, wrapWidth, tabWidth), lineHeight)
+		}
+		if i < len(files)-1 {
+			cursor.addSeparator(p.config.SeparatorLineHeight())
+		}
+	}
+	return Layout{Placements: placements, Pages: cursor.ranges, TotalPages: cursor.page}
+}
+
+// LayoutPages trả về số trang code của file Word đầy đủ theo layout (số dùng để quyết định tạo file rút gọn).
+func (p *Paginator) LayoutPages(files []models.CodeFile) int {
+	return p.FullLayout(files).TotalPages
+}
+
+// LineRangeParts đổi các khoảng hàng toàn cục (excerpt_boundaries = lines) thành ExcerptPart để dựng và
+// tính bố cục như các cách cắt khác: hàng header / separator chỉ quyết định file nào có mặt,
+// dòng bị cắt giữa các hàng tiếp nối được lấy nguyên dòng.
+func (p *Paginator) LineRangeParts(files []models.CodeFile, ranges []LineRange) []ExcerptPart {
+	spans := make(map[int][]LineRange)
+	wrapWidth, tabWidth := p.config.CodeWrapWidth(), p.config.Indentation.TabWidth
+
+	for _, lineRange := range ranges {
+		fileStartLine := 0
+		for i, file := range files {
+			rows := FileRows(file, wrapWidth, tabWidth)
+			contentStart := fileStartLine + p.config.CompactHeaderLines
+
+			firstRow := max(0, lineRange.Start-contentStart)
+			lastRow := min(rows-1, lineRange.End-contentStart)
+			if firstRow <= lastRow {
+				spans[i] = append(spans[i], LineRange{
+					Start: RowLine(file, wrapWidth, tabWidth, firstRow),
+					End:   RowLine(file, wrapWidth, tabWidth, lastRow),
+				})
+			}
+			fileStartLine = contentStart + rows + p.config.FileSeparatorLines
+		}
+	}
+	return partsFromSpans(files, spans)
+}
@@ -0,0 +1,176 @@
+// strategy.go - Excerpt strategies: which parts of the full document go into the shortened one
+package paginator
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Window là Pages trang liên tiếp của file rút gọn, đặt ở vị trí Anchor của file đầy đủ (0 = đầu, 1 = cuối).
+type Window struct {
+	Anchor float64
+	Pages  int
+}
+
+// ExcerptSelection là kết quả của một ExcerptStrategy: các cửa sổ trang (áp dụng được cho mọi cách cắt:
+// theo hàng, theo khai báo, theo trang cố định) hoặc các đoạn file / dòng đã chọn sẵn.
+type ExcerptSelection struct {
+	Windows []Window
+	Parts   []ExcerptPart
+}
+
+// ExcerptStrategy chọn nội dung của file rút gọn (excerpt_strategy).
+type ExcerptStrategy interface {
+	Name() string
+	Select(files []models.CodeFile) (ExcerptSelection, error)
+}
+
+// NewExcerptStrategy trả về chiến lược có sẵn theo excerpt_strategy.
+func NewExcerptStrategy(cfg *config.Config) ExcerptStrategy {
+	switch cfg.ExcerptStrategy {
+	case config.ExcerptFirstLast:
+		return FirstLast(cfg.TargetPages)
+	case config.ExcerptEvenlySpaced:
+		return EvenlySpaced(cfg.TargetPages, cfg.ExcerptSectionCount())
+	case config.ExcerptCurated:
+		return Curated(cfg.ExcerptRanges)
+	default:
+		return windowStrategy{name: config.ExcerptFirstMiddleLast, targetPages: cfg.TargetPages, sections: 3}
+	}
+}
+
+// ✅ windowStrategy chia targetPages thành sections cửa sổ cách đều nhau, cửa sổ đầu ở đầu và cửa sổ cuối ở cuối
+type windowStrategy struct {
+	name        string
+	targetPages int
+	sections    int
+}
+
+// FirstLast lấy nửa đầu targetPages từ đầu và nửa còn lại từ cuối file đầy đủ.
+func FirstLast(targetPages int) ExcerptStrategy {
+	return windowStrategy{name: config.ExcerptFirstLast, targetPages: targetPages, sections: 2}
+}
+
+// EvenlySpaced lấy sections cửa sổ cách đều nhau, tổng cộng targetPages trang.
+func EvenlySpaced(targetPages, sections int) ExcerptStrategy {
+	return windowStrategy{name: config.ExcerptEvenlySpaced, targetPages: targetPages, sections: sections}
+}
+
+func (s windowStrategy) Name() string {
+	if s.name == config.ExcerptEvenlySpaced {
+		return fmt.Sprintf("%s (%d sections)", s.name, s.sections)
+	}
+	return s.name
+}
+
+func (s windowStrategy) Select(files []models.CodeFile) (ExcerptSelection, error) {
+	sections := max(1, s.sections)
+	windows := make([]Window, sections)
+	for i := range windows {
+		// Phần dư của phép chia dồn cho các cửa sổ đầu
+		windows[i].Pages = s.targetPages / sections
+		if i < s.targetPages%sections {
+			windows[i].Pages++
+		}
+		if sections > 1 {
+			windows[i].Anchor = float64(i) / float64(sections-1)
+		}
+	}
+	return ExcerptSelection{Windows: windows}, nil
+}
+
+// ✅ curatedStrategy lấy đúng các file / khoảng dòng khai báo trong excerpt_ranges, theo thứ tự file của document
+type curatedStrategy struct {
+	ranges []config.ExcerptRange
+}
+
+// Curated trả về chiến lược chọn tay theo excerpt_ranges.
+func Curated(ranges []config.ExcerptRange) ExcerptStrategy {
+	return curatedStrategy{ranges: ranges}
+}
+
+func (s curatedStrategy) Name() string {
+	return config.ExcerptCurated
+}
+
+func (s curatedStrategy) Select(files []models.CodeFile) (ExcerptSelection, error) {
+	spans := make(map[int][]LineRange)
+	for i, r := range s.ranges {
+		glob, err := config.CompileGlob(r.File, true)
+		if err != nil {
+			return ExcerptSelection{}, fmt.Errorf("excerpt_ranges[%d]: file %q: %v", i, r.File, err)
+		}
+		first, last, err := r.LineSpan()
+		if err != nil {
+			return ExcerptSelection{}, fmt.Errorf("excerpt_ranges[%d]: %v", i, err)
+		}
+
+		// Đường dẫn cụ thể phải có đủ dòng; glob thì bỏ qua các file ngắn hơn khoảng dòng
+		literal := !strings.ContainsAny(r.File, "*?[")
+		matched, selected := false, false
+		for fileIndex, file := range files {
+			if !glob.MatchString(file.RelPath) || len(file.Lines) == 0 {
+				continue
+			}
+			matched = true
+			span := LineRange{Start: 0, End: len(file.Lines) - 1}
+			if first > 0 {
+				if first > len(file.Lines) {
+					if literal {
+						return ExcerptSelection{}, fmt.Errorf("excerpt_ranges[%d]: %s has only %d lines (lines %s)",
+							i, file.RelPath, len(file.Lines), r.Lines)
+					}
+					continue
+				}
+				span = LineRange{Start: first - 1, End: min(last, len(file.Lines)) - 1}
+			}
+			selected = true
+			spans[fileIndex] = append(spans[fileIndex], span)
+		}
+		switch {
+		case !matched:
+			return ExcerptSelection{}, fmt.Errorf("excerpt_ranges[%d]: no included file matches %q", i, r.File)
+		case !selected:
+			return ExcerptSelection{}, fmt.Errorf("excerpt_ranges[%d]: no file matching %q has line %d (lines %s)",
+				i, r.File, first, r.Lines)
+		}
+	}
+	return ExcerptSelection{Parts: partsFromSpans(files, spans)}, nil
+}
+
+// partsFromSpans dựng ExcerptPart từ các khoảng dòng của từng file (gộp khoảng chồng nhau / liền nhau),
+// theo thứ tự file; số dòng bị lược tính trên toàn bộ các file.
+func partsFromSpans(files []models.CodeFile, spans map[int][]LineRange) []ExcerptPart {
+	var parts []ExcerptPart
+	globalStart, previousEnd := 0, 0
+	for fileIndex, file := range files {
+		fileSpans := spans[fileIndex]
+		sort.Slice(fileSpans, func(i, j int) bool { return fileSpans[i].Start < fileSpans[j].Start })
+
+		var merged []LineRange
+		for _, span := range fileSpans {
+			if n := len(merged); n > 0 && span.Start <= merged[n-1].End+1 {
+				merged[n-1].End = max(merged[n-1].End, span.End)
+				continue
+			}
+			merged = append(merged, span)
+		}
+
+		for _, span := range merged {
+			parts = append(parts, ExcerptPart{
+				FileIndex:     fileIndex,
+				StartLine:     span.Start,
+				EndLine:       span.End,
+				Continued:     span.Start > 0,
+				Separator:     span.End == len(file.Lines)-1 && fileIndex < len(files)-1,
+				OmittedBefore: globalStart + span.Start - previousEnd,
+			})
+			previousEnd = globalStart + span.End + 1
+		}
+		globalStart += len(file.Lines)
+	}
+	return parts
+}
@@ -0,0 +1,62 @@
+// wrap.go - Split long code lines into continuation rows and count rows for pagination
+package paginator
+
+import (
+	"copyright-code-word/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// WrapLine chia một dòng thành các hàng tối đa width cột (theo rune, không cắt đôi ký tự UTF-8).
+// Tab chiếm tới tab stop kế tiếp (tabWidth cột); width <= 0 thì không wrap.
+func WrapLine(line string, width, tabWidth int) []string {
+	if width <= 0 || (utf8.RuneCountInString(line) <= width && !strings.ContainsRune(line, '\t')) {
+		return []string{line}
+	}
+
+	var rows []string
+	start, column := 0, 0
+	for i, r := range line {
+		advance := 1
+		if r == '\t' && tabWidth > 0 {
+			advance = tabWidth - column%tabWidth
+		}
+		if column > 0 && column+advance > width {
+			rows = append(rows, line[start:i])
+			start, column = i, 0
+			if r == '\t' && tabWidth > 0 {
+				advance = tabWidth
+			}
+		}
+		column += advance
+	}
+	return append(rows, line[start:])
+}
+
+// LineRows trả về số hàng mà một dòng chiếm sau khi wrap.
+func LineRows(line string, width, tabWidth int) int {
+	if width <= 0 || (utf8.RuneCountInString(line) <= width && !strings.ContainsRune(line, '\t')) {
+		return 1
+	}
+	return len(WrapLine(line, width, tabWidth))
+}
+
+// FileRows trả về tổng số hàng code của file (dòng dài được tính thêm hàng tiếp nối).
+func FileRows(file models.CodeFile, width, tabWidth int) int {
+	rows := 0
+	for _, line := range file.Lines {
+		rows += LineRows(line, width, tabWidth)
+	}
+	return rows
+}
+
+// RowLine trả về dòng (index) chứa hàng row của file, row tính từ 0.
+func RowLine(file models.CodeFile, width, tabWidth, row int) int {
+	for i, line := range file.Lines {
+		row -= LineRows(line, width, tabWidth)
+		if row < 0 {
+			return i
+		}
+	}
+	return len(file.Lines) - 1
+}
@@ -0,0 +1,95 @@
+// detectors.go - Built-in secret detectors (regex + entropy)
+package redactor
+
+import (
+	"copyright-code-word/config"
+	"math"
+	"regexp"
+)
+
+// detector tìm secret trong một dòng; group là capture group cần che (0 = cả đoạn khớp).
+type detector struct {
+	name       string
+	confidence string
+	pattern    *regexp.Regexp
+	group      int
+	check      func(value string) bool // Điều kiện bổ sung (entropy...), nil = luôn đúng
+}
+
+// ✅ Marker của private key nhiều dòng
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+func builtinDetector(name string, entropyThreshold float64) detector {
+	switch name {
+	case "aws-access-key":
+		return detector{name: name, confidence: config.ConfidenceHigh,
+			pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)}
+	case "jwt":
+		return detector{name: name, confidence: config.ConfidenceHigh,
+			pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)}
+	case "bearer-token":
+		return detector{name: name, confidence: config.ConfidenceHigh, group: 1,
+			pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{20,}=*)`)}
+	case "connection-string":
+		// Chỉ khớp bên trong chuỗi ("...;Password=x;" hoặc "Password=x;..."), không khớp `user.Password = x;`
+		return detector{name: name, confidence: config.ConfidenceHigh, group: 1,
+			pattern: regexp.MustCompile(`(?i)[;"']\s*(?:password|pwd)\s*=\s*([^;"'\s]+)`)}
+	case "url-credentials":
+		return detector{name: name, confidence: config.ConfidenceHigh, group: 1,
+			pattern: regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@"']+:([^/\s@"']+)@`)}
+	case "secret-assignment":
+		return detector{name: name, confidence: config.ConfidenceHigh, group: 1,
+			pattern: regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token|client[_-]?secret|private[_-]?key)[\w-]*["']?\s*[:=]\s*@?["']([^"'\s]{4,})["']`)}
+	case "high-entropy":
+		return detector{name: name, confidence: config.ConfidenceLow, group: 1,
+			pattern: regexp.MustCompile("[\"'`]([A-Za-z0-9+/=_\\-]{20,})[\"'`]"),
+			check: func(value string) bool {
+				return looksRandom(value) && shannonEntropy(value) >= entropyThreshold
+			}}
+	case "ipv4":
+		return detector{name: name, confidence: config.ConfidenceLow,
+			pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)}
+	case "email":
+		return detector{name: name, confidence: config.ConfidenceLow,
+			pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)}
+	case "url":
+		return detector{name: name, confidence: config.ConfidenceLow,
+			pattern: regexp.MustCompile(`\bhttps?://[^\s"'<>)\]]+`)}
+	}
+	return detector{}
+}
+
+// shannonEntropy tính entropy (bits/ký tự) của chuỗi.
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range value {
+		counts[r]++
+	}
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// looksRandom loại các chuỗi chỉ có chữ (tên hằng, identifier dài): cần có cả chữ và số.
+func looksRandom(value string) bool {
+	hasDigit, hasLetter := false, false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			hasLetter = true
+		}
+	}
+	return hasDigit && hasLetter
+}
@@ -0,0 +1,303 @@
+// redactor.go - Mask secrets in line content between fileprocessor and generator
+package redactor
+
+import (
+	"copyright-code-word/config"
+	"copyright-code-word/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding là một secret được phát hiện (không lưu giá trị secret).
+type Finding struct {
+	File       string
+	Line       int // Bắt đầu từ 1, khớp với số dòng trong file Word
+	Detector   string
+	Confidence string
+	Redacted   bool
+}
+
+type Redactor struct {
+	config    *config.Config
+	detectors []detector
+	allow     []*regexp.Regexp
+	findings  []Finding
+}
+
+// span là đoạn [start, end) cần che trong một dòng.
+type span struct {
+	start, end int
+	detector   *detector
+}
+
+func New(cfg *config.Config) (*Redactor, error) {
+	r := &Redactor{config: cfg}
+
+	for _, name := range cfg.Redaction.Detectors {
+		if name == "private-key" {
+			continue // Xử lý riêng vì có thể kéo dài nhiều dòng
+		}
+		r.detectors = append(r.detectors, builtinDetector(name, cfg.Redaction.EntropyThreshold))
+	}
+
+	// ✅ Rule tự định nghĩa trong file config
+	for _, rule := range cfg.Redaction.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule %q: %v", rule.Name, err)
+		}
+		confidence := rule.Confidence
+		if confidence == "" {
+			confidence = config.ConfidenceHigh
+		}
+		group := 0
+		if pattern.NumSubexp() > 0 {
+			group = 1
+		}
+		r.detectors = append(r.detectors, detector{name: rule.Name, confidence: confidence, pattern: pattern, group: group})
+	}
+
+	for _, pattern := range cfg.Redaction.Allow {
+		allow, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction allow pattern %q: %v", pattern, err)
+		}
+		r.allow = append(r.allow, allow)
+	}
+
+	return r, nil
+}
+
+// Findings trả về các secret đã phát hiện ở lần Redact gần nhất.
+func (r *Redactor) Findings() []Finding {
+	return r.findings
+}
+
+// Redact trả về bản sao của files với secret đã được che (mode "redact").
+// Số dòng không đổi nên số trang và số dòng trong file Word vẫn khớp với source.
+// Trả về lỗi nếu còn secret độ tin cậy cao chưa được che và on_unredacted = fail.
+func (r *Redactor) Redact(files []models.CodeFile) ([]models.CodeFile, error) {
+	r.findings = nil
+	if r.config.Redaction.Mode == config.RedactModeOff {
+		return files, nil
+	}
+
+	redacted := make([]models.CodeFile, len(files))
+	for i, file := range files {
+		redacted[i] = r.redactFile(file)
+	}
+
+	r.printSummary()
+
+	if unredacted := r.unredactedHighConfidence(); unredacted > 0 {
+		message := fmt.Sprintf("%d high-confidence secrets left unredacted (redaction.mode = %s)",
+			unredacted, r.config.Redaction.Mode)
+		if r.config.Redaction.OnUnredacted == config.OnUnredactedFail {
+			return nil, fmt.Errorf("%s; use --redact=redact or set redaction.on_unredacted: warn", message)
+		}
+		fmt.Printf("⚠️  Warning: %s\n", message)
+	}
+
+	return redacted, nil
+}
+
+func (r *Redactor) redactFile(file models.CodeFile) models.CodeFile {
+	mask := r.config.Redaction.Mode == config.RedactModeRedact
+	checkPrivateKey := containsString(r.config.Redaction.Detectors, "private-key")
+	inPrivateKey := false
+
+	lines := make([]string, len(file.Lines))
+	changed := false
+	for i, line := range file.Lines {
+		var spans []span
+
+		// ✅ Private key: che phần thân giữa BEGIN và END (có thể nhiều dòng)
+		if checkPrivateKey {
+			var keySpans []span
+			keySpans, inPrivateKey = privateKeySpans(line, inPrivateKey)
+			spans = append(spans, keySpans...)
+		}
+
+		for d := range r.detectors {
+			spans = append(spans, r.detect(&r.detectors[d], line)...)
+		}
+
+		spans = mergeSpans(spans)
+		for _, s := range spans {
+			r.findings = append(r.findings, Finding{
+				File:       file.RelPath,
+				Line:       i + 1,
+				Detector:   s.detector.name,
+				Confidence: s.detector.confidence,
+				Redacted:   mask,
+			})
+		}
+
+		if mask && len(spans) > 0 {
+			line = applyMask(line, spans, r.config.Redaction.Mask)
+			changed = true
+		}
+		lines[i] = line
+	}
+
+	if !changed {
+		return file
+	}
+
+	file.Lines = lines
+	file.Content = strings.Join(lines, "\n") + "\n"
+	return file
+}
+
+// detect trả về các đoạn khớp của một detector, bỏ qua giá trị nằm trong allow list.
+func (r *Redactor) detect(d *detector, line string) []span {
+	var spans []span
+	for _, match := range d.pattern.FindAllStringSubmatchIndex(line, -1) {
+		start, end := match[2*d.group], match[2*d.group+1]
+		if start < 0 || start == end {
+			continue
+		}
+		value := line[start:end]
+		if d.check != nil && !d.check(value) {
+			continue
+		}
+		if r.isAllowed(value) {
+			continue
+		}
+		spans = append(spans, span{start: start, end: end, detector: d})
+	}
+	return spans
+}
+
+func (r *Redactor) isAllowed(value string) bool {
+	for _, allow := range r.allow {
+		if allow.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+var privateKeyDetector = detector{name: "private-key", confidence: config.ConfidenceHigh}
+
+// privateKeySpans xử lý block private key; inKey cho biết dòng trước còn nằm trong block.
+func privateKeySpans(line string, inKey bool) ([]span, bool) {
+	start := 0
+	if !inKey {
+		begin := privateKeyBegin.FindStringIndex(line)
+		if begin == nil {
+			return nil, false
+		}
+		start = begin[1]
+	} else {
+		// Giữ nguyên indent để dòng không bị lệch
+		start = len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+
+	end, stillInKey := len(line), true
+	if loc := privateKeyEnd.FindStringIndex(line[start:]); loc != nil {
+		end, stillInKey = start+loc[0], false
+	}
+
+	// Bỏ dấu nháy / \n của string literal ở hai đầu
+	body := line[start:end]
+	trimmedLeft := strings.TrimPrefix(strings.TrimLeft(body, "\"' \t"), `\n`)
+	start += len(body) - len(trimmedLeft)
+	end = start + len(strings.TrimSuffix(strings.TrimRight(trimmedLeft, "\"'+,; \t"), `\n`))
+
+	if end <= start {
+		return nil, stillInKey
+	}
+	return []span{{start: start, end: end, detector: &privateKeyDetector}}, stillInKey
+}
+
+// mergeSpans sắp xếp và gộp các đoạn chồng nhau (giữ detector có độ tin cậy cao hơn).
+func mergeSpans(spans []span) []span {
+	if len(spans) < 2 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	merged := []span{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start >= last.end {
+			merged = append(merged, s)
+			continue
+		}
+		if s.end > last.end {
+			last.end = s.end
+		}
+		if s.detector.confidence == config.ConfidenceHigh && last.detector.confidence != config.ConfidenceHigh {
+			last.detector = s.detector
+		}
+	}
+	return merged
+}
+
+// applyMask thay từng đoạn bằng mask cố định (không lộ độ dài secret).
+func applyMask(line string, spans []span, mask string) string {
+	var b strings.Builder
+	previous := 0
+	for _, s := range spans {
+		b.WriteString(line[previous:s.start])
+		b.WriteString(mask)
+		previous = s.end
+	}
+	b.WriteString(line[previous:])
+	return b.String()
+}
+
+func (r *Redactor) unredactedHighConfidence() int {
+	count := 0
+	for _, f := range r.findings {
+		if !f.Redacted && f.Confidence == config.ConfidenceHigh {
+			count++
+		}
+	}
+	return count
+}
+
+// ✅ In thống kê redaction
+func (r *Redactor) printSummary() {
+	if len(r.findings) == 0 {
+		r.config.Logf(config.VerbosityNormal, "🔐 Redaction: no secrets found\n")
+		return
+	}
+
+	files := make(map[string]bool)
+	high := 0
+	for _, f := range r.findings {
+		files[f.File] = true
+		if f.Confidence == config.ConfidenceHigh {
+			high++
+		}
+
+		switch {
+		case !f.Redacted:
+			fmt.Printf("⚠️  Secret found: %s:%d (%s, %s confidence)\n", f.File, f.Line, f.Detector, f.Confidence)
+		default:
+			r.config.Logf(config.VerbosityVerbose, "🔐 Redacted: %s:%d (%s)\n", f.File, f.Line, f.Detector)
+		}
+	}
+
+	action := "masked"
+	if r.config.Redaction.Mode != config.RedactModeRedact {
+		action = "found (not masked)"
+	}
+	fmt.Printf("🔐 Redaction: %d secrets %s in %d files (%d high-confidence)\n",
+		len(r.findings), action, len(files), high)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}